@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// runCarryOver implements `tedo carryover [-dry-run] [-log]`
+func runCarryOver(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("carryover", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "List the todos that would be moved without moving them")
+	showLog := fs.Bool("log", false, "Show the todos carried over so far")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		return usageError("carryover takes no arguments")
+	}
+	if *dryRun && *showLog {
+		return usageError("-dry-run and -log cannot be combined")
+	}
+
+	if *showLog {
+		log, err := repo.GetCarryOverLog()
+		if err != nil {
+			return fail(err)
+		}
+		if len(log.Entries) == 0 {
+			fmt.Println("Nothing has been carried over yet.")
+		}
+		for _, entry := range log.Entries {
+			fmt.Printf("%s %s %s → %s %s\n", entry.At.Format("2006-01-02 15:04"), entry.ID, entry.From, entry.To, entry.Title)
+		}
+		return exitOK
+	}
+
+	today := models.TodayString()
+	if *dryRun {
+		overdue, err := repo.OverdueTodos(today)
+		if err != nil {
+			return fail(err)
+		}
+		for _, todo := range overdue {
+			fmt.Println(formatTodoLine(todo))
+		}
+		fmt.Printf("%d unfinished todo(s) would be moved to %s.\n", len(overdue), today)
+		return exitOK
+	}
+
+	carried, err := repo.CarryOver(today)
+	if err != nil {
+		return fail(err)
+	}
+	for _, todo := range carried {
+		fmt.Println(formatTodoLine(todo))
+	}
+	fmt.Printf("Carried %d unfinished todo(s) over to %s.\n", len(carried), today)
+	return exitOK
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// runCheck implements `tedo check ID` and its add, done and rm actions
+func runCheck(repo *storage.Repository, args []string) int {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return usageError("expected a todo ID")
+	}
+
+	todo, err := repo.FindTodo(args[0])
+	if err != nil {
+		return fail(err)
+	}
+
+	if len(args) == 1 {
+		printChecklist(todo)
+		return exitOK
+	}
+
+	switch args[1] {
+	case "add":
+		return runCheckAdd(repo, todo, args[2:])
+	case "done":
+		return runCheckDone(repo, todo, args[2:])
+	case "rm":
+		return runCheckRemove(repo, todo, args[2:])
+	default:
+		return usageError("unknown check action %q, expected add, done or rm", args[1])
+	}
+}
+
+// runCheckAdd implements `tedo check ID add TEXT`
+func runCheckAdd(repo *storage.Repository, todo models.Todo, args []string) int {
+	fs := flag.NewFlagSet("check add", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	title := strings.Join(strings.Fields(strings.Join(positional, " ")), " ")
+	if title == "" {
+		return usageError("an item title is required")
+	}
+
+	todo.AddChecklistItem(title)
+	if err := repo.UpdateTodo(&todo); err != nil {
+		return fail(err)
+	}
+
+	printChecklist(todo)
+	return exitOK
+}
+
+// runCheckDone implements `tedo check ID done N [-undo]`
+func runCheckDone(repo *storage.Repository, todo models.Todo, args []string) int {
+	fs := flag.NewFlagSet("check done", flag.ContinueOnError)
+	undo := fs.Bool("undo", false, "Mark the item as not done")
+	i, code := checklistItemArg(todo, fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	if todo.IsRecurring() && !todo.IsOccurrence() {
+		return usageError("%s repeats, check items of a single occurrence with %s", todo.ID, models.OccurrenceID(todo.ID, "YYYY-MM-DD"))
+	}
+
+	todo.Checklist[i].Done = !*undo
+	if err := repo.UpdateTodo(&todo); err != nil {
+		return fail(err)
+	}
+
+	printChecklist(todo)
+	return exitOK
+}
+
+// runCheckRemove implements `tedo check ID rm N`
+func runCheckRemove(repo *storage.Repository, todo models.Todo, args []string) int {
+	fs := flag.NewFlagSet("check rm", flag.ContinueOnError)
+	i, code := checklistItemArg(todo, fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	todo.RemoveChecklistItem(i)
+	if err := repo.UpdateTodo(&todo); err != nil {
+		return fail(err)
+	}
+
+	printChecklist(todo)
+	return exitOK
+}
+
+// checklistItemArg parses an action that takes exactly one checklist item
+// number, counted from 1, and returns its index
+func checklistItemArg(todo models.Todo, fs *flag.FlagSet, args []string) (int, int) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return 0, exitUsage
+	}
+	if len(positional) != 1 {
+		return 0, usageError("expected exactly one item number")
+	}
+
+	n, err := strconv.Atoi(positional[0])
+	if err != nil || n < 1 || n > len(todo.Checklist) {
+		return 0, usageError("item must be a number from 1 to %d", len(todo.Checklist))
+	}
+	return n - 1, exitOK
+}
+
+// printChecklist prints a todo followed by its numbered checklist items
+func printChecklist(todo models.Todo) {
+	fmt.Println(formatTodoLine(todo))
+	for i, item := range todo.Checklist {
+		checkbox := "[ ]"
+		if item.Done {
+			checkbox = "[x]"
+		}
+		fmt.Printf("  %d. %s %s\n", i+1, checkbox, item.Title)
+	}
+}
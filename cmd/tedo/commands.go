@@ -0,0 +1,640 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/dateparse"
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/query"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// Exit codes returned by subcommands
+const (
+	exitOK       = 0
+	exitError    = 1
+	exitUsage    = 2
+	exitNotFound = 3
+	exitLocked   = 4
+)
+
+// command runs a subcommand against the repository and returns its exit code
+type command func(repo *storage.Repository, args []string) int
+
+// commands maps subcommand names to their implementations
+var commands = map[string]command{
+	"add":       runAdd,
+	"list":      runList,
+	"ls":        runList,
+	"done":      runDone,
+	"edit":      runEdit,
+	"rm":        runRemove,
+	"check":     runCheck,
+	"lists":     runLists,
+	"migrate":   runMigrate,
+	"carryover": runCarryOver,
+	"config":    runConfig,
+	"trash":     runTrash,
+}
+
+// printCommandUsage prints the subcommand section of the help text
+func printCommandUsage() {
+	fmt.Println("\nCommands:")
+	fmt.Println("  tedo add TITLE [-desc TEXT] [-priority P] [-tags TAGS] [-date DATE | -general | -list LIST]")
+	fmt.Println("           [-repeat RULE] [-blocked-by IDS]              Add a todo (today by default)")
+	fmt.Println("  tedo list [-date DATE | -general | -list LIST | -all | -query Q] [-priority P] [-tag TAGS] [-status S] [-json]")
+	fmt.Println("                                                         List todos (today by default)")
+	fmt.Println("  tedo done ID [-undo] [-all]                            Mark a todo (and with -all its checklist) as done")
+	fmt.Println("  tedo edit ID [-title TEXT] [-desc TEXT] [-priority P] [-status S] [-tags TAGS]")
+	fmt.Println("           [-date DATE | -general | -list LIST] [-repeat RULE] [-blocked-by IDS]")
+	fmt.Println("                                                         Edit or move a todo")
+	fmt.Println("  tedo rm ID                                             Move a todo to the trash")
+	fmt.Println("  tedo check ID [add TEXT | done N [-undo] | rm N]       Show or change a todo's checklist")
+	fmt.Println("  tedo lists [add NAME | rename LIST NAME | archive LIST [-undo] | rm LIST]")
+	fmt.Println("                                                         Show or manage lists")
+	fmt.Println("  tedo trash [restore ID | purge ID | empty] [-json]     Show, restore or purge deleted todos")
+	fmt.Println("  tedo migrate [-dry-run]                                Upgrade data files to the current format")
+	fmt.Println("  tedo carryover [-dry-run] [-log]                       Move unfinished todos from past days to today")
+	fmt.Println("  tedo config [KEY [VALUE]]                              Show or change a setting")
+	fmt.Println("\nDATE is YYYY-MM-DD or an expression such as today, tomorrow, next fri,")
+	fmt.Println("in 3 days, +2w, end of month or nov 3.")
+	fmt.Println("RULE is a repeat rule such as daily, weekdays, every mon,wed, every 3 days,")
+	fmt.Println("monthly on 1 or monthly on last fri, optionally ending with until DATE or x10.")
+	fmt.Println("P is a priority: none, low, medium or high; list -priority shows that level and above.")
+	fmt.Println("S is a status: open, in-progress, waiting, done or cancelled.")
+	fmt.Println("TAGS is a list of tags such as \"#work #home\" or work,home; list -tag shows")
+	fmt.Println("todos that carry all of them.")
+	fmt.Println("LIST is a list name or ID; -general is the default list.")
+	fmt.Println("IDS are the todos that must be done first, separated by commas; blocking a")
+	fmt.Println("todo on one of its own dependents is rejected.")
+	fmt.Println("Q is a query such as 'due:<7d tag:work status:open prio>=high text:\"deploy\" list:backend'.")
+	fmt.Println("Terms must all match; join them with or, negate them with not or -, and group")
+	fmt.Println("them with parentheses. due takes a DATE, an offset such as 7d, none or any.")
+	fmt.Println("KEY is a setting: carry_over decides what happens to unfinished todos from")
+	fmt.Println("past days when tedo starts: move, prompt (the default) or leave;")
+	fmt.Println("confirm decides what the app asks about before deleting: always, permanent")
+	fmt.Println("(the default, only what the trash cannot bring back) or never;")
+	fmt.Println("upcoming_days is how many days ahead the Upcoming tab looks, or unlimited;")
+	fmt.Println("trash_days is how many days deleted todos are kept, or forever.")
+	fmt.Println("IDs may be shortened to any unique prefix. A single occurrence of a")
+	fmt.Println("recurring todo is addressed as ID@YYYY-MM-DD.")
+	fmt.Println("Exit codes: 0 success, 1 error, 2 usage, 3 todo not found, 4 data directory locked")
+}
+
+// parseArgs parses flags that may appear before or after positional
+// arguments and returns the positional arguments
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// fail reports err on stderr and maps it to an exit code
+func fail(err error) int {
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+
+	switch {
+	case errors.Is(err, storage.ErrTodoNotFound), errors.Is(err, storage.ErrAmbiguousID),
+		errors.Is(err, storage.ErrListNotFound):
+		return exitNotFound
+	case errors.Is(err, storage.ErrLocked):
+		return exitLocked
+	default:
+		return exitError
+	}
+}
+
+// usageError reports a usage problem and returns exitUsage
+func usageError(format string, args ...any) int {
+	fmt.Fprintf(os.Stderr, "Error: "+format+"\n", args...)
+	return exitUsage
+}
+
+// checkListFlag rejects -list combined with -date or -general
+func checkListFlag(listFlag, dateFlag string, general bool) error {
+	if listFlag != "" && (dateFlag != "" || general) {
+		return fmt.Errorf("-list cannot be combined with -date or -general")
+	}
+	return nil
+}
+
+// resolveDate turns the -date and -general flags into a todo date
+func resolveDate(dateFlag string, general bool) (*string, error) {
+	if general {
+		if dateFlag != "" {
+			return nil, fmt.Errorf("-date and -general cannot be combined")
+		}
+		return nil, nil
+	}
+
+	if dateFlag == "" {
+		today := models.TodayString()
+		return &today, nil
+	}
+
+	date, err := dateparse.ParseString(dateFlag)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// runAdd implements `tedo add`
+func runAdd(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	description := fs.String("desc", "", "Description of the todo")
+	dateFlag := fs.String("date", "", "Date of the todo (default today)")
+	general := fs.Bool("general", false, "Add to the general list instead of a date")
+	listFlag := fs.String("list", "", "Add to this list instead of a date")
+	repeat := fs.String("repeat", "", "Repeat rule, e.g. daily, weekdays or monthly on 1")
+	priorityFlag := fs.String("priority", "", "Priority: none, low, medium or high")
+	tagsFlag := fs.String("tags", "", "Tags, e.g. \"#work #home\"")
+	blockedBy := fs.String("blocked-by", "", "IDs of todos that must be done first")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	title := strings.Join(strings.Fields(strings.Join(positional, " ")), " ")
+	if title == "" {
+		return usageError("a title is required")
+	}
+
+	if err := checkListFlag(*listFlag, *dateFlag, *general); err != nil {
+		return usageError("%v", err)
+	}
+	date, err := resolveDate(*dateFlag, *general || *listFlag != "")
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	recurrence, err := dateparse.ParseRecurrence(*repeat)
+	if err != nil {
+		return usageError("%v", err)
+	}
+	if recurrence != nil && date == nil {
+		return usageError("%v", storage.ErrRecurringNeedsDate)
+	}
+
+	priority, err := models.ParsePriority(*priorityFlag)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	tags, err := models.ParseTags(*tagsFlag)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	todo := models.NewTodo(title, strings.TrimSpace(*description), date)
+	todo.Priority = priority
+	todo.Tags = tags
+	if *listFlag != "" {
+		list, err := repo.FindList(*listFlag)
+		if err != nil {
+			return fail(err)
+		}
+		todo.SetList(list.ID)
+	}
+	todo.Recurrence = recurrence
+	if todo.BlockedBy, err = repo.ResolveBlockers(todo, parseBlockers(*blockedBy)); err != nil {
+		return fail(err)
+	}
+	if err := repo.AddTodo(todo); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(todo.ID)
+	return exitOK
+}
+
+// runList implements `tedo list`
+func runList(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	dateFlag := fs.String("date", "", "List todos for this date (default today)")
+	general := fs.Bool("general", false, "List general todos")
+	listFlag := fs.String("list", "", "List the todos of this list")
+	all := fs.Bool("all", false, "List every todo")
+	asJSON := fs.Bool("json", false, "Print todos as JSON")
+	priorityFlag := fs.String("priority", "", "Only list todos at or above this priority")
+	tagFlag := fs.String("tag", "", "Only list todos carrying all of these tags")
+	statusFlag := fs.String("status", "", "Only list todos with this status")
+	queryFlag := fs.String("query", "", "List the todos of every date and list matching this query")
+	fs.StringVar(queryFlag, "q", "", "Shorthand for -query")
+	if _, err := parseArgs(fs, args); err != nil {
+		return exitUsage
+	}
+
+	minPriority, err := models.ParsePriority(*priorityFlag)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	tags, err := models.ParseTags(*tagFlag)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	var status models.Status
+	if *statusFlag != "" {
+		if status, err = models.ParseStatus(*statusFlag); err != nil {
+			return usageError("%v", err)
+		}
+	}
+
+	// filter applies the -priority, -tag and -status flags
+	filter := func(todos []models.Todo) []models.Todo {
+		return filterByPriority(filterByStatus(models.FilterByTags(todos, tags), status), minPriority)
+	}
+
+	var todos []models.Todo
+	if *queryFlag != "" {
+		if *all || *general || *dateFlag != "" || *listFlag != "" {
+			return usageError("-query cannot be combined with -all, -date, -general or -list; use due: and list: in the query")
+		}
+		q, err := query.Parse(*queryFlag)
+		if err != nil {
+			return usageError("%v", err)
+		}
+		todos, err := repo.Query(q, time.Now())
+		if err != nil {
+			return fail(err)
+		}
+		return printTodos(repo, filter(todos), *asJSON)
+	}
+
+	if *all {
+		if *general || *dateFlag != "" || *listFlag != "" {
+			return usageError("-all cannot be combined with -date, -general or -list")
+		}
+		todos, err := repo.GetAllTodos()
+		if err != nil {
+			return fail(err)
+		}
+		return printTodos(repo, filter(todos), *asJSON)
+	}
+
+	if err := checkListFlag(*listFlag, *dateFlag, *general); err != nil {
+		return usageError("%v", err)
+	}
+	date, err := resolveDate(*dateFlag, *general)
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	if *listFlag != "" {
+		list, err := repo.FindList(*listFlag)
+		if err != nil {
+			return fail(err)
+		}
+		todos, err = repo.GetListTodos(list.ID)
+	} else if date == nil {
+		todos, err = repo.GetGeneralTodos()
+	} else {
+		todos, err = repo.GetTodosForDate(*date)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	return printTodos(repo, filter(todos), *asJSON)
+}
+
+// filterByStatus keeps todos with the given status; an empty status keeps all
+func filterByStatus(todos []models.Todo, status models.Status) []models.Todo {
+	if status == "" {
+		return todos
+	}
+
+	var kept []models.Todo
+	for _, todo := range todos {
+		if todo.CurrentStatus() == status {
+			kept = append(kept, todo)
+		}
+	}
+	return kept
+}
+
+// filterByPriority keeps todos at or above min and sorts them for display
+func filterByPriority(todos []models.Todo, min models.Priority) []models.Todo {
+	var kept []models.Todo
+	for _, todo := range todos {
+		if todo.Priority >= min {
+			kept = append(kept, todo)
+		}
+	}
+	models.SortTodos(kept)
+	return kept
+}
+
+// printTodos writes todos to stdout, one per line or as a JSON array
+func printTodos(repo *storage.Repository, todos []models.Todo, asJSON bool) int {
+	if asJSON {
+		if todos == nil {
+			todos = []models.Todo{}
+		}
+		data, err := json.MarshalIndent(todos, "", "  ")
+		if err != nil {
+			return fail(err)
+		}
+		fmt.Println(string(data))
+		return exitOK
+	}
+
+	deps, err := repo.LoadDependencies()
+	if err != nil {
+		return fail(err)
+	}
+
+	for _, todo := range todos {
+		fmt.Println(formatTodoLine(todo) + formatBlockers(todo, deps))
+	}
+	return exitOK
+}
+
+// statusCheckbox renders a status as a plain text checkbox
+func statusCheckbox(status models.Status) string {
+	switch status {
+	case models.StatusInProgress:
+		return "[>]"
+	case models.StatusWaiting:
+		return "[w]"
+	case models.StatusDone:
+		return "[x]"
+	case models.StatusCancelled:
+		return "[-]"
+	default:
+		return "[ ]"
+	}
+}
+
+// formatBlockers lists the IDs of the open todos that todo waits on
+func formatBlockers(todo models.Todo, deps storage.Dependencies) string {
+	if todo.Completed {
+		return ""
+	}
+
+	var ids []string
+	for _, blocker := range deps.OpenBlockers(todo) {
+		ids = append(ids, blocker.ID)
+	}
+	if len(ids) == 0 {
+		return ""
+	}
+	return " (blocked by " + strings.Join(ids, ", ") + ")"
+}
+
+// parseBlockers splits a -blocked-by value into todo IDs
+func parseBlockers(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
+
+// formatTodoLine renders a todo as a single line of plain text
+func formatTodoLine(todo models.Todo) string {
+	checkbox := statusCheckbox(todo.CurrentStatus())
+
+	date := fmt.Sprintf("%-10s", todo.ListID())
+	if todo.Date != nil {
+		date = *todo.Date
+	}
+
+	title := todo.Title
+	if todo.Priority != models.PriorityNone {
+		title = strings.Repeat("!", int(todo.Priority)) + " " + title
+	}
+
+	line := fmt.Sprintf("%s %s %s %s", todo.ID, checkbox, date, title)
+	if todo.Description != "" {
+		line += " - " + todo.Description
+	}
+	if done, total := todo.ChecklistProgress(); total > 0 {
+		line += fmt.Sprintf(" [%d/%d]", done, total)
+	}
+	if len(todo.Tags) > 0 {
+		line += " " + models.FormatTags(todo.Tags)
+	}
+	if todo.IsRecurring() {
+		line += " (↻ " + todo.Recurrence.String() + ")"
+	}
+	if todo.CarriedFrom != "" {
+		line += " (carried from " + todo.CarriedFrom + ")"
+	}
+	return line + formatDeletedAt(todo)
+}
+
+// todoArg parses a subcommand that takes exactly one todo ID and looks it up
+func todoArg(repo *storage.Repository, fs *flag.FlagSet, args []string) (models.Todo, int) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return models.Todo{}, exitUsage
+	}
+	if len(positional) != 1 {
+		return models.Todo{}, usageError("expected exactly one todo ID")
+	}
+
+	todo, err := repo.FindTodo(positional[0])
+	if err != nil {
+		return models.Todo{}, fail(err)
+	}
+	return todo, exitOK
+}
+
+// runDone implements `tedo done`
+func runDone(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("done", flag.ContinueOnError)
+	undo := fs.Bool("undo", false, "Mark the todo as not done")
+	all := fs.Bool("all", false, "Also mark every checklist item as done")
+	todo, code := todoArg(repo, fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	if todo.IsRecurring() && !todo.IsOccurrence() {
+		return usageError("%s repeats, mark a single occurrence with %s", todo.ID, models.OccurrenceID(todo.ID, "YYYY-MM-DD"))
+	}
+	if *all && *undo {
+		return usageError("-all cannot be combined with -undo")
+	}
+
+	if *undo {
+		todo.SetStatus(models.StatusOpen)
+	} else {
+		todo.SetStatus(models.StatusDone)
+	}
+	if *all && !*undo {
+		todo.CompleteChecklist()
+	}
+	if err := repo.UpdateTodo(&todo); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(formatTodoLine(todo))
+	if todo.Completed {
+		deps, err := repo.LoadDependencies()
+		if err != nil {
+			return fail(err)
+		}
+		for _, dependent := range deps.Unblocked(todo.ID) {
+			fmt.Printf("unblocked %s\n", formatTodoLine(dependent))
+		}
+	}
+	return exitOK
+}
+
+// runEdit implements `tedo edit`
+func runEdit(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	title := fs.String("title", "", "New title")
+	description := fs.String("desc", "", "New description")
+	dateFlag := fs.String("date", "", "Move the todo to this date")
+	general := fs.Bool("general", false, "Move the todo to the general list")
+	listFlag := fs.String("list", "", "Move the todo to this list")
+	repeat := fs.String("repeat", "", `New repeat rule, "" to stop repeating`)
+	priorityFlag := fs.String("priority", "", "New priority: none, low, medium or high")
+	tagsFlag := fs.String("tags", "", `New tags, "" to remove all`)
+	blockedBy := fs.String("blocked-by", "", `IDs of todos that must be done first, "" to remove all`)
+	statusFlag := fs.String("status", "", "New status: open, in-progress, waiting, done or cancelled")
+	todo, code := todoArg(repo, fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	if err := checkListFlag(*listFlag, *dateFlag, *general); err != nil {
+		return usageError("%v", err)
+	}
+
+	// -general and -list file the todo in a list, dropping its date
+	targetList := ""
+	if *general {
+		targetList = models.DefaultListID
+	}
+	if *listFlag != "" {
+		list, err := repo.FindList(*listFlag)
+		if err != nil {
+			return fail(err)
+		}
+		targetList = list.ID
+	}
+
+	date := todo.Date
+	if *dateFlag != "" || targetList != "" {
+		var err error
+		if date, err = resolveDate(*dateFlag, targetList != ""); err != nil {
+			return usageError("%v", err)
+		}
+	}
+
+	changed, setBlockers := false, false
+	var flagErr error
+	fs.Visit(func(f *flag.Flag) {
+		var err error
+		switch f.Name {
+		case "title":
+			todo.Title = strings.Join(strings.Fields(*title), " ")
+			changed = true
+		case "desc":
+			todo.Description = strings.TrimSpace(*description)
+			changed = true
+		case "date", "general", "list":
+			changed = true
+		case "repeat":
+			todo.Recurrence, err = dateparse.ParseRecurrence(*repeat)
+			changed = true
+		case "priority":
+			todo.Priority, err = models.ParsePriority(*priorityFlag)
+			changed = true
+		case "tags":
+			todo.Tags, err = models.ParseTags(*tagsFlag)
+			changed = true
+		case "blocked-by":
+			setBlockers = true
+			changed = true
+		case "status":
+			var status models.Status
+			if status, err = models.ParseStatus(*statusFlag); err == nil {
+				todo.SetStatus(status)
+			}
+			changed = true
+		}
+		if err != nil && flagErr == nil {
+			flagErr = err
+		}
+	})
+
+	if flagErr != nil {
+		return usageError("%v", flagErr)
+	}
+	if setBlockers {
+		var err error
+		if todo.BlockedBy, err = repo.ResolveBlockers(todo, parseBlockers(*blockedBy)); err != nil {
+			return fail(err)
+		}
+	}
+	if todo.Recurrence != nil && date == nil {
+		return usageError("%v", storage.ErrRecurringNeedsDate)
+	}
+
+	if !changed {
+		return usageError("nothing to change, pass -title, -desc, -priority, -status, -tags, -blocked-by, -date, -general, -list or -repeat")
+	}
+	if todo.Title == "" {
+		return usageError("title cannot be empty")
+	}
+
+	var err error
+	if targetList != "" {
+		err = repo.MoveTodoToList(todo, targetList)
+	} else {
+		err = repo.MoveTodo(todo, date)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	// Moving a single occurrence detaches it into a new one-off todo
+	if todo.IsOccurrence() && (date == nil || *date != *todo.Date || todo.Recurrence == nil) {
+		fmt.Printf("%s was detached from its series\n", todo.ID)
+		return exitOK
+	}
+
+	todo.Date = date
+	if targetList != "" {
+		todo.SetList(targetList)
+	}
+	fmt.Println(formatTodoLine(todo))
+	return exitOK
+}
+
+// runRemove implements `tedo rm`
+func runRemove(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("rm", flag.ContinueOnError)
+	todo, code := todoArg(repo, fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	if err := repo.DeleteTodo(todo); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(todo.ID)
+	return exitOK
+}
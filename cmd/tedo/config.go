@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/WasathTheekshana/tedo/internal/config"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// runConfig implements `tedo config [KEY [VALUE]]`. Settings are stored apart
+// from the todos, so repo is not used.
+func runConfig(_ *storage.Repository, args []string) int {
+	if len(args) > 2 {
+		return usageError("usage: tedo config [KEY [VALUE]]")
+	}
+
+	path, err := config.ResolvePath()
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return fail(err)
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("# %s\n", cfg.Path())
+		fmt.Printf("carry_over = %s\n", cfg.CarryOver)
+		fmt.Printf("confirm = %s\n", cfg.Confirm)
+		fmt.Printf("upcoming_days = %s\n", formatUpcomingDays(cfg.UpcomingDays))
+		fmt.Printf("trash_days = %s\n", formatTrashDays(cfg.TrashDays))
+		for _, view := range cfg.Views {
+			fmt.Printf("view %q = %s\n", view.Name, view.Query)
+		}
+		return exitOK
+	}
+
+	switch args[0] {
+	case "carry_over", "carry-over", "carryover":
+		if len(args) == 1 {
+			fmt.Println(cfg.CarryOver)
+			return exitOK
+		}
+		policy, err := config.ParseCarryOverPolicy(args[1])
+		if err != nil {
+			return usageError("%v", err)
+		}
+		cfg.CarryOver = policy
+		if err := cfg.Save(); err != nil {
+			return fail(err)
+		}
+		fmt.Printf("carry_over = %s\n", cfg.CarryOver)
+
+	case "confirm":
+		if len(args) == 1 {
+			fmt.Println(cfg.Confirm)
+			return exitOK
+		}
+		policy, err := config.ParseConfirmPolicy(args[1])
+		if err != nil {
+			return usageError("%v", err)
+		}
+		cfg.Confirm = policy
+		if err := cfg.Save(); err != nil {
+			return fail(err)
+		}
+		fmt.Printf("confirm = %s\n", cfg.Confirm)
+
+	case "upcoming_days", "upcoming-days":
+		if len(args) == 1 {
+			fmt.Println(formatUpcomingDays(cfg.UpcomingDays))
+			return exitOK
+		}
+		days, err := config.ParseUpcomingDays(args[1])
+		if err != nil {
+			return usageError("%v", err)
+		}
+		cfg.UpcomingDays = days
+		if err := cfg.Save(); err != nil {
+			return fail(err)
+		}
+		fmt.Printf("upcoming_days = %s\n", formatUpcomingDays(cfg.UpcomingDays))
+
+	case "trash_days", "trash-days":
+		if len(args) == 1 {
+			fmt.Println(formatTrashDays(cfg.TrashDays))
+			return exitOK
+		}
+		days, err := config.ParseTrashDays(args[1])
+		if err != nil {
+			return usageError("%v", err)
+		}
+		cfg.TrashDays = days
+		if err := cfg.Save(); err != nil {
+			return fail(err)
+		}
+		fmt.Printf("trash_days = %s\n", formatTrashDays(cfg.TrashDays))
+
+	default:
+		return usageError("unknown setting %q, expected carry_over, confirm, upcoming_days or trash_days", args[0])
+	}
+	return exitOK
+}
+
+// formatUpcomingDays prints a horizon the way tedo config accepts it
+func formatUpcomingDays(days int) string {
+	if days == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(days)
+}
+
+// formatTrashDays prints a retention the way tedo config accepts it
+func formatTrashDays(days int) string {
+	if days == 0 {
+		return "forever"
+	}
+	return fmt.Sprint(days)
+}
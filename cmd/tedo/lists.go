@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// runLists implements `tedo lists` and its add, rename, archive and rm actions
+func runLists(repo *storage.Repository, args []string) int {
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "add":
+			return runListsAdd(repo, args[1:])
+		case "rename":
+			return runListsRename(repo, args[1:])
+		case "archive":
+			return runListsArchive(repo, args[1:])
+		case "rm":
+			return runListsRemove(repo, args[1:])
+		default:
+			return usageError("unknown lists action %q, expected add, rename, archive or rm", args[0])
+		}
+	}
+
+	fs := flag.NewFlagSet("lists", flag.ContinueOnError)
+	archived := fs.Bool("archived", false, "Include archived lists")
+	asJSON := fs.Bool("json", false, "Print lists as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	lists, err := repo.GetLists()
+	if err != nil {
+		return fail(err)
+	}
+
+	var shown []models.List
+	for _, list := range lists {
+		if *archived || !list.Archived {
+			shown = append(shown, list)
+		}
+	}
+
+	if *asJSON {
+		if shown == nil {
+			shown = []models.List{}
+		}
+		data, err := json.MarshalIndent(shown, "", "  ")
+		if err != nil {
+			return fail(err)
+		}
+		fmt.Println(string(data))
+		return exitOK
+	}
+
+	for _, list := range shown {
+		todos, err := repo.GetListTodos(list.ID)
+		if err != nil {
+			return fail(err)
+		}
+
+		line := fmt.Sprintf("%-16s %s (%d todos)", list.ID, list.Name, len(todos))
+		if list.Archived {
+			line += " [archived]"
+		}
+		fmt.Println(line)
+	}
+	return exitOK
+}
+
+// runListsAdd implements `tedo lists add NAME`
+func runListsAdd(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("lists add", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	name := strings.Join(positional, " ")
+	if strings.TrimSpace(name) == "" {
+		return usageError("a list name is required")
+	}
+
+	list, err := repo.CreateList(name)
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(list.ID)
+	return exitOK
+}
+
+// runListsRename implements `tedo lists rename LIST NAME`
+func runListsRename(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("lists rename", flag.ContinueOnError)
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) < 2 {
+		return usageError("expected a list and its new name")
+	}
+
+	list, err := repo.FindList(positional[0])
+	if err != nil {
+		return fail(err)
+	}
+	if err := repo.RenameList(list.ID, strings.Join(positional[1:], " ")); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(list.ID)
+	return exitOK
+}
+
+// runListsArchive implements `tedo lists archive LIST [-undo]`
+func runListsArchive(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("lists archive", flag.ContinueOnError)
+	undo := fs.Bool("undo", false, "Restore an archived list")
+	list, code := listArg(repo, fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	if err := repo.SetListArchived(list.ID, !*undo); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(list.ID)
+	return exitOK
+}
+
+// runListsRemove implements `tedo lists rm LIST`
+func runListsRemove(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("lists rm", flag.ContinueOnError)
+	list, code := listArg(repo, fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	if err := repo.DeleteList(list.ID); err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(list.ID)
+	return exitOK
+}
+
+// listArg parses an action that takes exactly one list and looks it up
+func listArg(repo *storage.Repository, fs *flag.FlagSet, args []string) (models.List, int) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return models.List{}, exitUsage
+	}
+	if len(positional) != 1 {
+		return models.List{}, usageError("expected exactly one list")
+	}
+
+	list, err := repo.FindList(positional[0])
+	if err != nil {
+		return models.List{}, fail(err)
+	}
+	return list, exitOK
+}
@@ -4,7 +4,10 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/WasathTheekshana/tedo/internal/config"
+	"github.com/WasathTheekshana/tedo/internal/storage"
 	"github.com/WasathTheekshana/tedo/internal/ui"
 	"github.com/WasathTheekshana/tedo/internal/version"
 	tea "github.com/charmbracelet/bubbletea"
@@ -14,6 +17,7 @@ func main() {
 	// Command line flags
 	showVersion := flag.Bool("version", false, "Show version information")
 	showHelp := flag.Bool("help", false, "Show help information")
+	dataDirFlag := flag.String("data-dir", "", "Directory to store todos in (default $TEDO_HOME or $XDG_DATA_HOME/tedo)")
 	flag.Parse()
 
 	// Handle version flag
@@ -30,12 +34,66 @@ func main() {
 		fmt.Println("  tedo            Start the application")
 		fmt.Println("  tedo -version   Show version information")
 		fmt.Println("  tedo -help      Show this help message")
+		fmt.Println("  tedo -data-dir  Use a custom data directory")
+		printCommandUsage()
+		fmt.Println("\nData is stored in $TEDO_HOME, or $XDG_DATA_HOME/tedo (~/.local/share/tedo).")
+		fmt.Println("Settings are read from $TEDO_CONFIG, or $XDG_CONFIG_HOME/tedo/config.json (~/.config/tedo/config.json).")
 		fmt.Println("\nFor more information, visit: https://github.com/WasathTheekshana/Tedo")
 		os.Exit(0)
 	}
 
+	// Resolve where todos live
+	dataDir, err := storage.ResolveDataDir(*dataDirFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving data directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Move todos from the old ./data directory on first run
+	if copied, err := storage.MigrateLegacyDataDir(dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error migrating ./data: %v\n", err)
+		os.Exit(1)
+	} else if copied > 0 {
+		fmt.Fprintf(os.Stderr, "Migrated %d file(s) from ./data to %s\n", copied, dataDir)
+	}
+
+	// Undated todos used to share one file, they now form the default list
+	if moved, err := storage.MigrateGeneralFile(dataDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error migrating %s: %v\n", storage.GeneralFile, err)
+		os.Exit(1)
+	} else if moved {
+		fmt.Fprintf(os.Stderr, "Moved the todos of %s to the default list in the %s directory\n", storage.GeneralFile, storage.ListsDir)
+	}
+
+	repo := storage.NewRepository(storage.WithDataDir(dataDir))
+
+	// Run a subcommand instead of the TUI if one was given
+	if flag.NArg() > 0 {
+		cmd, ok := commands[flag.Arg(0)]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Unknown command %q. Run 'tedo -help' for usage.\n", flag.Arg(0))
+			os.Exit(exitUsage)
+		}
+		os.Exit(cmd(repo, flag.Args()[1:]))
+	}
+
+	// Settings such as the carry-over policy only matter to the TUI
+	configPath, err := config.ResolvePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error resolving config file: %v\n", err)
+		os.Exit(1)
+	}
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Changes made in the TUI can be undone, even after a restart
+	repo.RecordHistory(time.Now().Format(time.RFC3339Nano))
+
 	// Create the application model
-	model := ui.NewModel()
+	model := ui.NewModel(repo, cfg)
 
 	// Create the Bubble Tea program
 	p := tea.NewProgram(
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// runMigrate implements `tedo migrate [--dry-run]`
+func runMigrate(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Report what would change without rewriting files")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	results, err := repo.Migrate(*dryRun)
+	for _, result := range results {
+		fmt.Printf("%s: version %d\n", result.File, result.FromVersion)
+		for _, step := range result.Steps {
+			fmt.Printf("  - %s\n", step)
+		}
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	switch {
+	case len(results) == 0:
+		fmt.Println("All data files are up to date.")
+	case *dryRun:
+		fmt.Printf("%d file(s) would be migrated. Run without --dry-run to apply.\n", len(results))
+	default:
+		fmt.Printf("Migrated %d file(s). Originals were backed up to %s/.\n", len(results), storage.BackupDir)
+	}
+	return exitOK
+}
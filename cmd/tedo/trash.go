@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/config"
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// runTrash implements `tedo trash [restore ID | purge ID | empty]`. Todos
+// kept longer than the trash_days setting are purged first, except when
+// restoring, so a todo that just expired can still be brought back.
+func runTrash(repo *storage.Repository, args []string) int {
+	if len(args) == 0 || args[0] != "restore" {
+		if code := purgeExpiredTrash(repo); code != exitOK {
+			return code
+		}
+	}
+
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		switch args[0] {
+		case "restore":
+			return runTrashRestore(repo, args[1:])
+		case "purge":
+			return runTrashPurge(repo, args[1:])
+		case "empty":
+			return runTrashEmpty(repo, args[1:])
+		default:
+			return usageError("unknown trash action %q, expected restore, purge or empty", args[0])
+		}
+	}
+
+	fs := flag.NewFlagSet("trash", flag.ContinueOnError)
+	asJSON := fs.Bool("json", false, "Print the deleted todos as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		return usageError("unexpected argument %q", fs.Arg(0))
+	}
+
+	trash, err := repo.TrashedTodos()
+	if err != nil {
+		return fail(err)
+	}
+
+	if *asJSON {
+		data, err := json.MarshalIndent(trash, "", "  ")
+		if err != nil {
+			return fail(err)
+		}
+		fmt.Println(string(data))
+		return exitOK
+	}
+
+	if len(trash) == 0 {
+		fmt.Println("The trash is empty.")
+	}
+	for _, todo := range trash {
+		fmt.Println(formatTodoLine(todo))
+	}
+	return exitOK
+}
+
+// purgeExpiredTrash permanently deletes the todos kept in the trash for
+// longer than the trash_days setting
+func purgeExpiredTrash(repo *storage.Repository) int {
+	path, err := config.ResolvePath()
+	if err != nil {
+		return fail(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		return fail(err)
+	}
+	if cfg.TrashDays == 0 {
+		return exitOK
+	}
+
+	if _, err := repo.PurgeTrash(time.Now().AddDate(0, 0, -cfg.TrashDays)); err != nil {
+		return fail(err)
+	}
+	return exitOK
+}
+
+// runTrashRestore implements `tedo trash restore ID`
+func runTrashRestore(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("trash restore", flag.ContinueOnError)
+	ref, code := trashArg(fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	todo, err := repo.RestoreTodo(ref)
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(formatTodoLine(todo))
+	return exitOK
+}
+
+// runTrashPurge implements `tedo trash purge ID`
+func runTrashPurge(repo *storage.Repository, args []string) int {
+	fs := flag.NewFlagSet("trash purge", flag.ContinueOnError)
+	ref, code := trashArg(fs, args)
+	if code != exitOK {
+		return code
+	}
+
+	todo, err := repo.PurgeTodo(ref)
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Println(todo.ID)
+	return exitOK
+}
+
+// runTrashEmpty implements `tedo trash empty`
+func runTrashEmpty(repo *storage.Repository, args []string) int {
+	if len(args) > 0 {
+		return usageError("trash empty takes no arguments")
+	}
+
+	emptied, err := repo.EmptyTrash()
+	if err != nil {
+		return fail(err)
+	}
+
+	fmt.Printf("Deleted %d todo(s) for good.\n", emptied)
+	return exitOK
+}
+
+// trashArg parses an action that takes exactly one ID of a deleted todo
+func trashArg(fs *flag.FlagSet, args []string) (string, int) {
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return "", exitUsage
+	}
+	if len(positional) != 1 {
+		return "", usageError("expected exactly one todo ID")
+	}
+	return positional[0], exitOK
+}
+
+// formatDeletedAt describes when a todo was moved to the trash
+func formatDeletedAt(todo models.Todo) string {
+	if todo.DeletedAt == nil {
+		return ""
+	}
+	return " (deleted " + todo.DeletedAt.Format("2006-01-02 15:04") + ")"
+}
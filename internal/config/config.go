@@ -0,0 +1,259 @@
+// Package config loads and saves the user's tedo settings. Settings live
+// apart from the todos so that several data directories can share them.
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/WasathTheekshana/tedo/internal/atomicfile"
+)
+
+const (
+	// EnvVar overrides the location of the config file
+	EnvVar = "TEDO_CONFIG"
+
+	// FileName is the name of the config file in the config directory
+	FileName = "config.json"
+)
+
+// CarryOverPolicy decides what happens to unfinished todos from past days
+type CarryOverPolicy string
+
+const (
+	CarryOverMove   CarryOverPolicy = "move"   // move them to today automatically
+	CarryOverPrompt CarryOverPolicy = "prompt" // ask once a day whether to move them
+	CarryOverLeave  CarryOverPolicy = "leave"  // leave them on their dates
+)
+
+// ParseCarryOverPolicy parses a carry-over policy name
+func ParseCarryOverPolicy(s string) (CarryOverPolicy, error) {
+	switch policy := CarryOverPolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case CarryOverMove, CarryOverPrompt, CarryOverLeave:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown carry-over policy %q, expected move, prompt or leave", s)
+}
+
+// ConfirmPolicy decides which deletions the app asks about first
+type ConfirmPolicy string
+
+const (
+	ConfirmAlways    ConfirmPolicy = "always"    // ask before every deletion, also of todos moved to the trash
+	ConfirmPermanent ConfirmPolicy = "permanent" // ask before deleting what the trash cannot bring back
+	ConfirmNever     ConfirmPolicy = "never"     // never ask
+)
+
+// ParseConfirmPolicy parses a confirm policy name
+func ParseConfirmPolicy(s string) (ConfirmPolicy, error) {
+	switch policy := ConfirmPolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case ConfirmAlways, ConfirmPermanent, ConfirmNever:
+		return policy, nil
+	}
+	return "", fmt.Errorf("unknown confirm policy %q, expected always, permanent or never", s)
+}
+
+// Asks reports whether a deletion has to be confirmed first; permanent is
+// true when what is deleted cannot be restored from the trash
+func (p ConfirmPolicy) Asks(permanent bool) bool {
+	switch p {
+	case ConfirmAlways:
+		return true
+	case ConfirmNever:
+		return false
+	default:
+		return permanent
+	}
+}
+
+// UpcomingHorizons are the horizons, in days, that the Upcoming tab cycles
+// through; 0 shows everything planned
+var UpcomingHorizons = []int{7, 30, 90, 0}
+
+// NextUpcomingHorizon returns the horizon after days in UpcomingHorizons
+func NextUpcomingHorizon(days int) int {
+	for i, horizon := range UpcomingHorizons {
+		if horizon == days {
+			return UpcomingHorizons[(i+1)%len(UpcomingHorizons)]
+		}
+	}
+	return UpcomingHorizons[0]
+}
+
+// ParseUpcomingDays parses a horizon in days, or "unlimited" for none
+func ParseUpcomingDays(s string) (int, error) {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "unlimited", "all", "none":
+		return 0, nil
+	}
+
+	days, err := strconv.Atoi(s)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid upcoming horizon %q, expected a number of days or unlimited", s)
+	}
+	return days, nil
+}
+
+// FormatUpcomingDays describes a horizon, e.g. "next 30 days"
+func FormatUpcomingDays(days int) string {
+	switch days {
+	case 0:
+		return "unlimited"
+	case 1:
+		return "next day"
+	}
+	return fmt.Sprintf("next %d days", days)
+}
+
+// ParseTrashDays parses how many days deleted todos stay in the trash, or
+// "forever" to never purge them
+func ParseTrashDays(s string) (int, error) {
+	switch s = strings.ToLower(strings.TrimSpace(s)); s {
+	case "forever", "never", "unlimited":
+		return 0, nil
+	}
+
+	days, err := strconv.Atoi(s)
+	if err != nil || days < 0 {
+		return 0, fmt.Errorf("invalid trash retention %q, expected a number of days or forever", s)
+	}
+	return days, nil
+}
+
+// View is a saved query shown as its own tab
+type View struct {
+	Name  string `json:"name"`
+	Query string `json:"query"`
+}
+
+// Config holds the user's settings
+type Config struct {
+	CarryOver CarryOverPolicy `json:"carry_over,omitempty"`
+
+	// Confirm decides which deletions are asked about first
+	Confirm ConfirmPolicy `json:"confirm,omitempty"`
+
+	// UpcomingDays is how far ahead the Upcoming tab looks; 0 is unlimited
+	UpcomingDays int `json:"upcoming_days"`
+
+	// TrashDays is how long deleted todos stay in the trash; 0 keeps them
+	TrashDays int `json:"trash_days"`
+
+	// Views are the saved views, in the order of their tabs
+	Views []View `json:"views,omitempty"`
+
+	path string // file the config was loaded from and is saved to
+}
+
+// Default returns the settings used when no config file exists
+func Default() Config {
+	return Config{CarryOver: CarryOverPrompt, Confirm: ConfirmPermanent, UpcomingDays: 30, TrashDays: 30}
+}
+
+// ResolvePath returns the config file location: $TEDO_CONFIG, then
+// $XDG_CONFIG_HOME/tedo/config.json, falling back to ~/.config/tedo/config.json
+func ResolvePath() (string, error) {
+	if path := os.Getenv(EnvVar); path != "" {
+		return filepath.Abs(path)
+	}
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "tedo", FileName), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "tedo", FileName), nil
+}
+
+// Load reads the config file at path. A missing file gives the defaults;
+// settings missing from the file keep their default values.
+func Load(path string) (Config, error) {
+	cfg := Default()
+	cfg.path = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.CarryOver == "" {
+		cfg.CarryOver = Default().CarryOver
+	}
+	if _, err := ParseCarryOverPolicy(string(cfg.CarryOver)); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.Confirm == "" {
+		cfg.Confirm = Default().Confirm
+	}
+	if _, err := ParseConfirmPolicy(string(cfg.Confirm)); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	if cfg.UpcomingDays < 0 {
+		return cfg, fmt.Errorf("invalid config %s: upcoming_days cannot be negative", path)
+	}
+	if cfg.TrashDays < 0 {
+		return cfg, fmt.Errorf("invalid config %s: trash_days cannot be negative", path)
+	}
+	for _, view := range cfg.Views {
+		if strings.TrimSpace(view.Name) == "" {
+			return cfg, fmt.Errorf("invalid config %s: every view needs a name", path)
+		}
+	}
+	return cfg, nil
+}
+
+// Path returns the file the config is saved to
+func (c Config) Path() string {
+	return c.path
+}
+
+// Save writes the config back to the file it was loaded from. The file is
+// replaced in one step so a crash never leaves half a config behind.
+func (c Config) Save() error {
+	if c.path == "" {
+		return fmt.Errorf("config has no file to save to")
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	dir := filepath.Dir(c.path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory %s: %w", dir, err)
+	}
+	if err := atomicfile.WriteFile(c.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// Update reloads the file the config was loaded from, applies change to it
+// and saves it. Settings changed elsewhere since c was loaded, e.g. by tedo
+// config, are kept.
+func (c Config) Update(change func(cfg *Config)) error {
+	if c.path == "" {
+		return fmt.Errorf("config has no file to save to")
+	}
+
+	current, err := Load(c.path)
+	if err != nil {
+		return err
+	}
+	change(&current)
+	return current.Save()
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateKeepsOtherChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tedo", FileName)
+	app, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tedo config changes a setting while the app is running
+	cli, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cli.TrashDays = 7
+	if err := cli.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	views := []View{{Name: "Work", Query: "tag:work"}}
+	err = app.Update(func(cfg *Config) {
+		cfg.Views = views
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.TrashDays != 7 {
+		t.Errorf("trash_days is %d after saving the views, want the 7 set elsewhere", saved.TrashDays)
+	}
+	if !reflect.DeepEqual(saved.Views, views) {
+		t.Errorf("views are %+v, want %+v", saved.Views, views)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("config directory holds %d files, want only %s", len(entries), FileName)
+	}
+}
@@ -0,0 +1,476 @@
+// Package dateparse turns human date expressions such as "tomorrow",
+// "next fri", "in 3 days", "+2w", "end of month" or "nov 3" into dates.
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parser resolves date expressions relative to a clock
+type Parser struct {
+	now func() time.Time
+}
+
+// ParseError describes which part of an expression could not be parsed
+type ParseError struct {
+	Kind   string // what was being parsed, "date" if empty
+	Input  string // the full expression
+	Token  string // the part that failed, empty if the input ended early
+	Offset int    // byte offset of Token in Input
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	kind := e.Kind
+	if kind == "" {
+		kind = "date"
+	}
+
+	if e.Token == "" {
+		return fmt.Sprintf("cannot parse %s %q: %s", kind, e.Input, e.Reason)
+	}
+	return fmt.Sprintf("cannot parse %s %q at %q: %s", kind, e.Input, e.Token, e.Reason)
+}
+
+// New creates a parser that resolves expressions against now. A nil now
+// uses time.Now.
+func New(now func() time.Time) *Parser {
+	if now == nil {
+		now = time.Now
+	}
+	return &Parser{now: now}
+}
+
+// Parse resolves input against the current time
+func Parse(input string) (time.Time, error) {
+	return New(nil).Parse(input)
+}
+
+// ParseString resolves input against the current time and formats the
+// result as YYYY-MM-DD
+func ParseString(input string) (string, error) {
+	return New(nil).ParseString(input)
+}
+
+// token is a single word of the input along with its position
+type token struct {
+	text   string
+	offset int
+}
+
+// state walks the tokens of one expression
+type state struct {
+	input  string
+	tokens []token
+	pos    int
+	today  time.Time
+}
+
+var (
+	weekdays = map[string]time.Weekday{
+		"sun": time.Sunday, "sunday": time.Sunday,
+		"mon": time.Monday, "monday": time.Monday,
+		"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+		"wed": time.Wednesday, "wednesday": time.Wednesday,
+		"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+		"fri": time.Friday, "friday": time.Friday,
+		"sat": time.Saturday, "saturday": time.Saturday,
+	}
+
+	months = map[string]time.Month{
+		"jan": time.January, "january": time.January,
+		"feb": time.February, "february": time.February,
+		"mar": time.March, "march": time.March,
+		"apr": time.April, "april": time.April,
+		"may": time.May,
+		"jun": time.June, "june": time.June,
+		"jul": time.July, "july": time.July,
+		"aug": time.August, "august": time.August,
+		"sep": time.September, "sept": time.September, "september": time.September,
+		"oct": time.October, "october": time.October,
+		"nov": time.November, "november": time.November,
+		"dec": time.December, "december": time.December,
+	}
+
+	// relativePattern matches shorthand offsets such as +3, +2w or -1m
+	relativePattern = regexp.MustCompile(`^([+-])(\d+)([a-z]*)$`)
+
+	// ordinalPattern matches day numbers such as 3, 3rd or 21st
+	ordinalPattern = regexp.MustCompile(`^(\d{1,2})(st|nd|rd|th)?$`)
+)
+
+// Parse resolves input against the parser's clock. Weekday names refer to
+// the next such day on or after today; "next <weekday>" skips today. Month
+// and day expressions without a year refer to the next occurrence.
+func (p *Parser) Parse(input string) (time.Time, error) {
+	now := p.now()
+	s := &state{
+		input:  input,
+		tokens: tokenize(input),
+		today:  time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()),
+	}
+
+	if len(s.tokens) == 0 {
+		return time.Time{}, &ParseError{Input: input, Reason: "empty date"}
+	}
+
+	date, err := s.parseExpression()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if tok, ok := s.peek(); ok {
+		return time.Time{}, s.errorAt(tok, "unexpected text")
+	}
+	return date, nil
+}
+
+// ParseString resolves input like Parse and formats the result as YYYY-MM-DD
+func (p *Parser) ParseString(input string) (string, error) {
+	date, err := p.Parse(input)
+	if err != nil {
+		return "", err
+	}
+	return date.Format("2006-01-02"), nil
+}
+
+// tokenize splits input into lowercase words, ignoring commas
+func tokenize(input string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range input + " " {
+		if r == ' ' || r == '\t' || r == ',' {
+			if start >= 0 {
+				tokens = append(tokens, token{text: strings.ToLower(input[start:i]), offset: start})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	return tokens
+}
+
+// peek returns the current token without consuming it
+func (s *state) peek() (token, bool) {
+	if s.pos >= len(s.tokens) {
+		return token{}, false
+	}
+	return s.tokens[s.pos], true
+}
+
+// next consumes and returns the current token
+func (s *state) next() (token, bool) {
+	tok, ok := s.peek()
+	if ok {
+		s.pos++
+	}
+	return tok, ok
+}
+
+// errorAt builds a ParseError pointing at tok
+func (s *state) errorAt(tok token, reason string) error {
+	return &ParseError{
+		Input:  s.input,
+		Token:  s.input[tok.offset : tok.offset+len(tok.text)],
+		Offset: tok.offset,
+		Reason: reason,
+	}
+}
+
+// errorAtEnd builds a ParseError for input that ended too early
+func (s *state) errorAtEnd(reason string) error {
+	return &ParseError{Input: s.input, Offset: len(s.input), Reason: reason}
+}
+
+// parseExpression parses a complete date expression
+func (s *state) parseExpression() (time.Time, error) {
+	tok, _ := s.next()
+
+	if date, err := time.ParseInLocation("2006-01-02", tok.text, s.today.Location()); err == nil {
+		return date, nil
+	}
+
+	switch tok.text {
+	case "today", "tod", "now":
+		return s.today, nil
+	case "tomorrow", "tom", "tmr", "tmrw":
+		return s.today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return s.today.AddDate(0, 0, -1), nil
+	case "next":
+		return s.parseNext()
+	case "this":
+		return s.parseThis()
+	case "in":
+		return s.parseIn()
+	case "end":
+		return s.parseEndOf()
+	case "eow":
+		return endOf(s.today, "week"), nil
+	case "eom":
+		return endOf(s.today, "month"), nil
+	case "eoy":
+		return endOf(s.today, "year"), nil
+	}
+
+	if weekday, ok := weekdays[tok.text]; ok {
+		return nextWeekday(s.today, weekday, false), nil
+	}
+
+	if month, ok := months[tok.text]; ok {
+		return s.parseMonthDay(month)
+	}
+
+	if match := relativePattern.FindStringSubmatch(tok.text); match != nil {
+		return s.parseRelative(tok, match)
+	}
+
+	if ordinalPattern.MatchString(tok.text) {
+		return s.parseDayMonth(tok)
+	}
+
+	return time.Time{}, s.errorAt(tok, "unknown date")
+}
+
+// parseNext handles "next <weekday>" and "next week|month|year"
+func (s *state) parseNext() (time.Time, error) {
+	tok, ok := s.next()
+	if !ok {
+		return time.Time{}, s.errorAtEnd(`expected a weekday or "week", "month" or "year" after "next"`)
+	}
+
+	if weekday, ok := weekdays[tok.text]; ok {
+		return nextWeekday(s.today, weekday, true), nil
+	}
+
+	unit, ok := parseUnit(tok.text)
+	if !ok {
+		return time.Time{}, s.errorAt(tok, "unknown weekday or unit")
+	}
+	return addUnits(s.today, 1, unit), nil
+}
+
+// parseThis handles "this <weekday>"
+func (s *state) parseThis() (time.Time, error) {
+	tok, ok := s.next()
+	if !ok {
+		return time.Time{}, s.errorAtEnd(`expected a weekday after "this"`)
+	}
+
+	weekday, ok := weekdays[tok.text]
+	if !ok {
+		return time.Time{}, s.errorAt(tok, "unknown weekday")
+	}
+	return nextWeekday(s.today, weekday, false), nil
+}
+
+// parseIn handles "in <n> <unit>" and "in a <unit>"
+func (s *state) parseIn() (time.Time, error) {
+	tok, ok := s.next()
+	if !ok {
+		return time.Time{}, s.errorAtEnd(`expected a number after "in"`)
+	}
+
+	var n int
+	if tok.text == "a" || tok.text == "an" {
+		n = 1
+	} else {
+		var err error
+		if n, err = strconv.Atoi(tok.text); err != nil || n < 0 {
+			return time.Time{}, s.errorAt(tok, "expected a number")
+		}
+	}
+
+	unitTok, ok := s.next()
+	if !ok {
+		return time.Time{}, s.errorAtEnd("expected days, weeks, months or years")
+	}
+
+	unit, ok := parseUnit(unitTok.text)
+	if !ok {
+		return time.Time{}, s.errorAt(unitTok, "unknown unit")
+	}
+	return addUnits(s.today, n, unit), nil
+}
+
+// parseEndOf handles "end of week|month|year"
+func (s *state) parseEndOf() (time.Time, error) {
+	tok, ok := s.next()
+	if ok && tok.text == "of" {
+		tok, ok = s.next()
+	}
+	if !ok {
+		return time.Time{}, s.errorAtEnd(`expected "week", "month" or "year" after "end of"`)
+	}
+
+	unit, ok := parseUnit(tok.text)
+	if !ok || unit == "day" {
+		return time.Time{}, s.errorAt(tok, "expected week, month or year")
+	}
+	return endOf(s.today, unit), nil
+}
+
+// parseRelative handles shorthand offsets such as +3, +2w or -1m. The unit
+// may also be given as the following word ("+2 weeks").
+func (s *state) parseRelative(tok token, match []string) (time.Time, error) {
+	n, err := strconv.Atoi(match[2])
+	if err != nil {
+		return time.Time{}, s.errorAt(tok, "invalid number")
+	}
+	if match[1] == "-" {
+		n = -n
+	}
+
+	unitText := match[3]
+	if unitText == "" {
+		if next, ok := s.peek(); ok {
+			if _, isUnit := parseUnit(next.text); isUnit {
+				s.pos++
+				unitText = next.text
+			}
+		}
+	}
+	if unitText == "" {
+		unitText = "d"
+	}
+
+	unit, ok := parseUnit(unitText)
+	if !ok {
+		return time.Time{}, s.errorAt(tok, "unknown unit")
+	}
+	return addUnits(s.today, n, unit), nil
+}
+
+// parseMonthDay handles "<month> <day> [year]" after the month was consumed
+func (s *state) parseMonthDay(month time.Month) (time.Time, error) {
+	tok, ok := s.next()
+	if !ok {
+		return time.Time{}, s.errorAtEnd("expected a day after the month")
+	}
+
+	match := ordinalPattern.FindStringSubmatch(tok.text)
+	if match == nil {
+		return time.Time{}, s.errorAt(tok, "expected a day of the month")
+	}
+	day, _ := strconv.Atoi(match[1])
+
+	return s.finishDate(tok, month, day)
+}
+
+// parseDayMonth handles "<day> <month> [year]" after the day was consumed
+func (s *state) parseDayMonth(dayTok token) (time.Time, error) {
+	day, _ := strconv.Atoi(ordinalPattern.FindStringSubmatch(dayTok.text)[1])
+
+	tok, ok := s.next()
+	if !ok {
+		return time.Time{}, s.errorAtEnd("expected a month after the day")
+	}
+
+	month, ok := months[tok.text]
+	if !ok {
+		return time.Time{}, s.errorAt(tok, "unknown month")
+	}
+
+	return s.finishDate(dayTok, month, day)
+}
+
+// finishDate reads an optional year and validates the day of the month.
+// Without a year the next occurrence on or after today is used.
+func (s *state) finishDate(dayTok token, month time.Month, day int) (time.Time, error) {
+	year := s.today.Year()
+	explicitYear := false
+	if tok, ok := s.peek(); ok {
+		if y, err := strconv.Atoi(tok.text); err == nil && len(tok.text) == 4 {
+			year = y
+			explicitYear = true
+			s.pos++
+		}
+	}
+
+	if day < 1 || day > daysIn(year, month) {
+		return time.Time{}, s.errorAt(dayTok, fmt.Sprintf("day %d is out of range for %s", day, month))
+	}
+
+	date := time.Date(year, month, day, 0, 0, 0, 0, s.today.Location())
+	if !explicitYear && date.Before(s.today) {
+		date = time.Date(year+1, month, day, 0, 0, 0, 0, s.today.Location())
+		if date.Day() != day {
+			return time.Time{}, s.errorAt(dayTok, fmt.Sprintf("day %d is out of range for %s", day, month))
+		}
+	}
+	return date, nil
+}
+
+// parseUnit normalizes a unit word to day, week, month or year
+func parseUnit(text string) (string, bool) {
+	switch text {
+	case "d", "day", "days":
+		return "day", true
+	case "w", "wk", "wks", "week", "weeks":
+		return "week", true
+	case "m", "mo", "mon", "month", "months":
+		return "month", true
+	case "y", "yr", "yrs", "year", "years":
+		return "year", true
+	}
+	return "", false
+}
+
+// addUnits adds n units to date. Month and year arithmetic clamps to the
+// end of the month, so Jan 31 + 1 month is the last day of February.
+func addUnits(date time.Time, n int, unit string) time.Time {
+	switch unit {
+	case "week":
+		return date.AddDate(0, 0, 7*n)
+	case "month":
+		return addMonths(date, n)
+	case "year":
+		return addMonths(date, 12*n)
+	default:
+		return date.AddDate(0, 0, n)
+	}
+}
+
+// addMonths adds n months to date, clamping the day to the target month
+func addMonths(date time.Time, n int) time.Time {
+	first := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location()).AddDate(0, n, 0)
+	day := date.Day()
+	if last := daysIn(first.Year(), first.Month()); day > last {
+		day = last
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, date.Location())
+}
+
+// endOf returns the last day of the week (Saturday), month or year containing date
+func endOf(date time.Time, unit string) time.Time {
+	switch unit {
+	case "week":
+		return date.AddDate(0, 0, int(time.Saturday-date.Weekday()))
+	case "year":
+		return time.Date(date.Year(), time.December, 31, 0, 0, 0, 0, date.Location())
+	default:
+		return time.Date(date.Year(), date.Month(), daysIn(date.Year(), date.Month()), 0, 0, 0, 0, date.Location())
+	}
+}
+
+// nextWeekday returns the next date falling on weekday. Today counts unless
+// strictlyAfter is set.
+func nextWeekday(today time.Time, weekday time.Weekday, strictlyAfter bool) time.Time {
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 && strictlyAfter {
+		days = 7
+	}
+	return today.AddDate(0, 0, days)
+}
+
+// daysIn returns the number of days in a month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
@@ -0,0 +1,188 @@
+package dateparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fixedParser returns a parser whose clock always reads now
+func fixedParser(now time.Time) *Parser {
+	return New(func() time.Time { return now })
+}
+
+// testNow is Tuesday 2025-12-30, close to the end of the month and year
+var testNow = time.Date(2025, time.December, 30, 15, 4, 5, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2026-02-14", "2026-02-14"},
+		{"today", "2025-12-30"},
+		{"Now", "2025-12-30"},
+		{"tomorrow", "2025-12-31"},
+		{"tmrw", "2025-12-31"},
+		{"yesterday", "2025-12-29"},
+
+		// Relative offsets
+		{"+1", "2025-12-31"},
+		{"+2d", "2026-01-01"},
+		{"+2 days", "2026-01-01"},
+		{"-1w", "2025-12-23"},
+		{"+2 weeks", "2026-01-13"},
+		{"+1m", "2026-01-30"},
+		{"+2m", "2026-02-28"},
+		{"+1y", "2026-12-30"},
+		{"in 3 days", "2026-01-02"},
+		{"in a week", "2026-01-06"},
+		{"in 2 months", "2026-02-28"},
+		{"in an year", "2026-12-30"},
+		{"in 0 days", "2025-12-30"},
+
+		// Weekdays: today counts, unless after "next"
+		{"tue", "2025-12-30"},
+		{"this tuesday", "2025-12-30"},
+		{"next tue", "2026-01-06"},
+		{"wed", "2025-12-31"},
+		{"Thursday", "2026-01-01"},
+		{"fri", "2026-01-02"},
+		{"next fri", "2026-01-02"},
+		{"mon", "2026-01-05"},
+		{"sun", "2026-01-04"},
+
+		{"next week", "2026-01-06"},
+		{"next month", "2026-01-30"},
+		{"next year", "2026-12-30"},
+		{"eow", "2026-01-03"},
+		{"end of week", "2026-01-03"},
+		{"eom", "2025-12-31"},
+		{"end month", "2025-12-31"},
+		{"end of year", "2025-12-31"},
+
+		// Days of the month roll over to next year once they have passed
+		{"dec 31", "2025-12-31"},
+		{"dec 30", "2025-12-30"},
+		{"dec 25", "2026-12-25"},
+		{"jan 5", "2026-01-05"},
+		{"5 jan", "2026-01-05"},
+		{"1st january", "2026-01-01"},
+		{"DEC 24 2025", "2025-12-24"},
+		{"Nov 3, 2027", "2027-11-03"},
+		{"3rd march 2027", "2027-03-03"},
+		{"feb 29 2028", "2028-02-29"},
+	}
+
+	p := fixedParser(testNow)
+	for _, tt := range tests {
+		got, err := p.Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got.Format("2006-01-02"), tt.want)
+		}
+		if got.Hour() != 0 || got.Minute() != 0 || got.Location() != testNow.Location() {
+			t.Errorf("Parse(%q) = %s, want midnight in the clock's time zone", tt.input, got)
+		}
+	}
+}
+
+func TestParseMonthEnds(t *testing.T) {
+	tests := []struct {
+		now   time.Time
+		input string
+		want  string
+	}{
+		{time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC), "+1m", "2026-02-28"},
+		{time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC), "next month", "2026-02-28"},
+		{time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC), "eom", "2026-01-31"},
+		{time.Date(2028, time.January, 31, 9, 0, 0, 0, time.UTC), "+1m", "2028-02-29"},
+		{time.Date(2028, time.January, 10, 9, 0, 0, 0, time.UTC), "feb 29", "2028-02-29"},
+		{time.Date(2028, time.February, 29, 9, 0, 0, 0, time.UTC), "+1y", "2029-02-28"},
+		{time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC), "-1m", "2026-02-28"},
+		{time.Date(2026, time.February, 28, 23, 59, 0, 0, time.UTC), "tomorrow", "2026-03-01"},
+	}
+
+	for _, tt := range tests {
+		got, err := fixedParser(tt.now).Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) on %s: %v", tt.input, tt.now.Format("2006-01-02"), err)
+			continue
+		}
+		if got.Format("2006-01-02") != tt.want {
+			t.Errorf("Parse(%q) on %s = %s, want %s", tt.input, tt.now.Format("2006-01-02"), got.Format("2006-01-02"), tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		token  string
+		offset int
+		reason string
+	}{
+		{"", "", 0, "empty date"},
+		{"   ", "", 0, "empty date"},
+		{"someday", "someday", 0, "unknown date"},
+		{"2025-13-01", "2025-13-01", 0, "unknown date"},
+		{"next", "", 4, "expected a weekday"},
+		{"Next Blursday", "Blursday", 5, "unknown weekday or unit"},
+		{"this month", "month", 5, "unknown weekday"},
+		{"in", "", 2, "expected a number"},
+		{"in x days", "x", 3, "expected a number"},
+		{"in 3", "", 4, "expected days, weeks, months or years"},
+		{"in 3 fortnights", "fortnights", 5, "unknown unit"},
+		{"+3q", "+3q", 0, "unknown unit"},
+		{"end of day", "day", 7, "expected week, month or year"},
+		{"end of", "", 6, "expected"},
+		{"dec", "", 3, "expected a day after the month"},
+		{"dec first", "first", 4, "expected a day of the month"},
+		{"dec 32", "32", 4, "day 32 is out of range for December"},
+		{"31 feb", "31", 0, "day 31 is out of range for February"},
+		{"feb 29", "29", 4, "day 29 is out of range for February"},
+		{"15", "", 2, "expected a month after the day"},
+		{"15 smarch", "smarch", 3, "unknown month"},
+		{"tomorrow please", "please", 9, "unexpected text"},
+		{"fri, 9am", "9am", 5, "unexpected text"},
+	}
+
+	p := fixedParser(testNow)
+	for _, tt := range tests {
+		_, err := p.Parse(tt.input)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%q) error = %v, want a ParseError", tt.input, err)
+			continue
+		}
+		if perr.Input != tt.input || perr.Token != tt.token || perr.Offset != tt.offset {
+			t.Errorf("Parse(%q) failed at %q (offset %d), want %q (offset %d)", tt.input, perr.Token, perr.Offset, tt.token, tt.offset)
+		}
+		if !strings.Contains(perr.Reason, tt.reason) {
+			t.Errorf("Parse(%q) reason = %q, want it to contain %q", tt.input, perr.Reason, tt.reason)
+		}
+		if !strings.HasPrefix(perr.Error(), "cannot parse date ") {
+			t.Errorf("Parse(%q) error = %q", tt.input, perr.Error())
+		}
+	}
+}
+
+func TestParseUsesClockPerCall(t *testing.T) {
+	now := testNow
+	p := New(func() time.Time { return now })
+
+	for _, want := range []string{"2025-12-31", "2026-01-01"} {
+		got, err := p.ParseString("tomorrow")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("ParseString(tomorrow) = %s, want %s", got, want)
+		}
+		now = now.AddDate(0, 0, 1)
+	}
+}
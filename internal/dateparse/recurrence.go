@@ -0,0 +1,290 @@
+package dateparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// countPattern matches occurrence limits written as x10
+var countPattern = regexp.MustCompile(`^x(\d+)$`)
+
+// ParseRecurrence parses a repeat rule against the current time
+func ParseRecurrence(input string) (*models.Recurrence, error) {
+	return New(nil).ParseRecurrence(input)
+}
+
+// ParseRecurrence parses a repeat rule such as "daily", "weekdays",
+// "every mon,wed", "every 3 days", "monthly on 1", "monthly on 2nd tue" or
+// "monthly on last fri". A rule may end with "until <date>" and/or
+// "for <n> times" (or "x<n>"). An empty input returns nil.
+func (p *Parser) ParseRecurrence(input string) (*models.Recurrence, error) {
+	rule, err := p.parseRecurrence(input)
+	if parseErr, ok := err.(*ParseError); ok {
+		parseErr.Kind = "repeat rule"
+	}
+	return rule, err
+}
+
+// parseRecurrence does the work of ParseRecurrence
+func (p *Parser) parseRecurrence(input string) (*models.Recurrence, error) {
+	s := &state{input: input, tokens: tokenize(input)}
+	if len(s.tokens) == 0 {
+		return nil, nil
+	}
+
+	rule := &models.Recurrence{}
+	if err := s.parseCount(rule); err != nil {
+		return nil, err
+	}
+	if err := p.parseUntil(s, rule); err != nil {
+		return nil, err
+	}
+	if err := s.parseRule(rule); err != nil {
+		return nil, err
+	}
+
+	if tok, ok := s.peek(); ok {
+		return nil, s.errorAt(tok, "unexpected text")
+	}
+	if err := rule.Validate(); err != nil {
+		return nil, &ParseError{Input: input, Offset: 0, Reason: err.Error()}
+	}
+	return rule, nil
+}
+
+// parseCount strips a trailing "for <n> times", "<n> times" or "x<n>"
+func (s *state) parseCount(rule *models.Recurrence) error {
+	n := len(s.tokens)
+	last := s.tokens[n-1]
+
+	var countTok token
+	switch {
+	case countPattern.MatchString(last.text):
+		countTok = last
+		s.tokens = s.tokens[:n-1]
+		rule.Count, _ = strconv.Atoi(countPattern.FindStringSubmatch(last.text)[1])
+	case (last.text == "times" || last.text == "time") && n >= 2:
+		countTok = s.tokens[n-2]
+		count, err := strconv.Atoi(countTok.text)
+		if err != nil || count < 1 {
+			return s.errorAt(countTok, "expected a number of times")
+		}
+		rule.Count = count
+		s.tokens = s.tokens[:n-2]
+		if len(s.tokens) > 0 && s.tokens[len(s.tokens)-1].text == "for" {
+			s.tokens = s.tokens[:len(s.tokens)-1]
+		}
+	default:
+		return nil
+	}
+
+	if rule.Count < 1 {
+		return s.errorAt(countTok, "the number of times must be at least 1")
+	}
+	if len(s.tokens) == 0 {
+		return s.errorAtEnd("expected a repeat rule before the number of times")
+	}
+	return nil
+}
+
+// parseUntil strips a trailing "until <date expression>"
+func (p *Parser) parseUntil(s *state, rule *models.Recurrence) error {
+	for i, tok := range s.tokens {
+		if tok.text != "until" {
+			continue
+		}
+
+		rest := s.tokens[i+1:]
+		if len(rest) == 0 {
+			return s.errorAtEnd(`expected a date after "until"`)
+		}
+
+		last := rest[len(rest)-1]
+		expr := s.input[rest[0].offset : last.offset+len(last.text)]
+		until, err := p.Parse(expr)
+		if err != nil {
+			if parseErr, ok := err.(*ParseError); ok {
+				parseErr.Input = s.input
+				parseErr.Offset += rest[0].offset
+			}
+			return err
+		}
+
+		rule.Until = until.Format("2006-01-02")
+		s.tokens = s.tokens[:i]
+		if len(s.tokens) == 0 {
+			return s.errorAt(tok, "expected a repeat rule before")
+		}
+		return nil
+	}
+	return nil
+}
+
+// parseRule parses the frequency part of a rule
+func (s *state) parseRule(rule *models.Recurrence) error {
+	tok, _ := s.next()
+	every := tok.text == "every" || tok.text == "each"
+	if every {
+		var ok bool
+		if tok, ok = s.next(); !ok {
+			return s.errorAtEnd(`expected day, week, month, a weekday or a number after "every"`)
+		}
+	}
+
+	switch tok.text {
+	case "daily", "day":
+		rule.Frequency = models.Daily
+		return nil
+	case "weekdays", "weekday", "workdays", "workday":
+		rule.Frequency = models.Weekly
+		rule.Weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+		return nil
+	case "weekly", "week":
+		rule.Frequency = models.Weekly
+		return s.parseWeeklyOn(rule)
+	case "monthly", "month":
+		rule.Frequency = models.Monthly
+		return s.parseMonthlyOn(rule)
+	}
+
+	if _, ok := weekdays[tok.text]; ok {
+		s.pos--
+		rule.Frequency = models.Weekly
+		return s.parseWeekdayList(rule)
+	}
+
+	if n, err := strconv.Atoi(tok.text); err == nil && every {
+		if n < 1 {
+			return s.errorAt(tok, "the interval must be at least 1")
+		}
+		rule.Interval = n
+
+		unitTok, ok := s.next()
+		if !ok {
+			return s.errorAtEnd("expected days, weeks or months")
+		}
+		unit, ok := parseUnit(unitTok.text)
+		switch {
+		case ok && unit == "day":
+			rule.Frequency = models.Daily
+			return nil
+		case ok && unit == "week":
+			rule.Frequency = models.Weekly
+			return s.parseWeeklyOn(rule)
+		case ok && unit == "month":
+			rule.Frequency = models.Monthly
+			return s.parseMonthlyOn(rule)
+		}
+		return s.errorAt(unitTok, "expected days, weeks or months")
+	}
+
+	return s.errorAt(tok, "unknown repeat rule")
+}
+
+// parseWeeklyOn parses an optional "on <weekdays>" after a weekly rule
+func (s *state) parseWeeklyOn(rule *models.Recurrence) error {
+	tok, ok := s.peek()
+	if !ok {
+		return nil
+	}
+	if tok.text == "on" {
+		s.pos++
+	}
+	return s.parseWeekdayList(rule)
+}
+
+// parseWeekdayList parses weekdays separated by commas or "and"
+func (s *state) parseWeekdayList(rule *models.Recurrence) error {
+	for {
+		tok, ok := s.next()
+		if !ok {
+			break
+		}
+		if tok.text == "and" {
+			continue
+		}
+
+		weekday, ok := weekdays[tok.text]
+		if !ok {
+			return s.errorAt(tok, "unknown weekday")
+		}
+		rule.Weekdays = append(rule.Weekdays, weekday)
+	}
+
+	if len(rule.Weekdays) == 0 {
+		return s.errorAtEnd("expected at least one weekday")
+	}
+	return nil
+}
+
+// parseMonthlyOn parses an optional "on <day>", "on <nth> <weekday>" or
+// "on last <weekday>" after a monthly rule
+func (s *state) parseMonthlyOn(rule *models.Recurrence) error {
+	tok, ok := s.next()
+	if !ok {
+		return nil
+	}
+	if tok.text == "on" || tok.text == "the" {
+		if tok, ok = s.next(); ok && tok.text == "the" {
+			tok, ok = s.next()
+		}
+		if !ok {
+			return s.errorAtEnd(`expected a day after "on"`)
+		}
+	}
+
+	if tok.text == "last" {
+		rule.Week = models.LastWeek
+		return s.parseMonthlyWeekday(rule)
+	}
+
+	match := ordinalPattern.FindStringSubmatch(tok.text)
+	if match == nil {
+		if week, ok := ordinalWords[tok.text]; ok {
+			rule.Week = week
+			return s.parseMonthlyWeekday(rule)
+		}
+		return s.errorAt(tok, "expected a day of the month")
+	}
+
+	n, _ := strconv.Atoi(match[1])
+	if next, ok := s.peek(); ok {
+		if _, isWeekday := weekdays[next.text]; isWeekday {
+			if n < 1 || n > 5 {
+				return s.errorAt(tok, "expected 1st to 5th")
+			}
+			rule.Week = n
+			return s.parseMonthlyWeekday(rule)
+		}
+	}
+
+	if n < 1 || n > 31 {
+		return s.errorAt(tok, fmt.Sprintf("day %d is not a valid day of the month", n))
+	}
+	rule.MonthDay = n
+	return nil
+}
+
+// parseMonthlyWeekday parses the weekday of an nth weekday rule
+func (s *state) parseMonthlyWeekday(rule *models.Recurrence) error {
+	tok, ok := s.next()
+	if !ok {
+		return s.errorAtEnd("expected a weekday")
+	}
+
+	weekday, ok := weekdays[tok.text]
+	if !ok {
+		return s.errorAt(tok, "unknown weekday")
+	}
+	rule.Weekdays = []time.Weekday{weekday}
+	return nil
+}
+
+// ordinalWords maps spelled out ordinals to week numbers
+var ordinalWords = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5,
+}
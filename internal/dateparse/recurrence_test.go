@@ -0,0 +1,100 @@
+package dateparse
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	workdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	tests := []struct {
+		input string
+		want  *models.Recurrence
+	}{
+		{"", nil},
+		{"daily", &models.Recurrence{Frequency: models.Daily}},
+		{"every day", &models.Recurrence{Frequency: models.Daily}},
+		{"every 3 days", &models.Recurrence{Frequency: models.Daily, Interval: 3}},
+		{"weekdays", &models.Recurrence{Frequency: models.Weekly, Weekdays: workdays}},
+		{"weekly", &models.Recurrence{Frequency: models.Weekly}},
+		{"weekly on mon", &models.Recurrence{Frequency: models.Weekly, Weekdays: []time.Weekday{time.Monday}}},
+		{"every mon, wed", &models.Recurrence{Frequency: models.Weekly, Weekdays: []time.Weekday{time.Monday, time.Wednesday}}},
+		{"Every Tue and Thu", &models.Recurrence{Frequency: models.Weekly, Weekdays: []time.Weekday{time.Tuesday, time.Thursday}}},
+		{"every 2 weeks on fri", &models.Recurrence{Frequency: models.Weekly, Interval: 2, Weekdays: []time.Weekday{time.Friday}}},
+		{"monthly", &models.Recurrence{Frequency: models.Monthly}},
+		{"monthly on 15", &models.Recurrence{Frequency: models.Monthly, MonthDay: 15}},
+		{"monthly on the 31st", &models.Recurrence{Frequency: models.Monthly, MonthDay: 31}},
+		{"monthly on 2nd tue", &models.Recurrence{Frequency: models.Monthly, Week: 2, Weekdays: []time.Weekday{time.Tuesday}}},
+		{"monthly on last fri", &models.Recurrence{Frequency: models.Monthly, Week: models.LastWeek, Weekdays: []time.Weekday{time.Friday}}},
+		{"every 3 months on first mon", &models.Recurrence{Frequency: models.Monthly, Interval: 3, Week: 1, Weekdays: []time.Weekday{time.Monday}}},
+
+		// Limits, with until dates resolved against the parser's clock
+		{"daily until jan 5", &models.Recurrence{Frequency: models.Daily, Until: "2026-01-05"}},
+		{"daily until +1w", &models.Recurrence{Frequency: models.Daily, Until: "2026-01-06"}},
+		{"weekdays for 10 times", &models.Recurrence{Frequency: models.Weekly, Weekdays: workdays, Count: 10}},
+		{"daily 4 times", &models.Recurrence{Frequency: models.Daily, Count: 4}},
+		{"daily x5", &models.Recurrence{Frequency: models.Daily, Count: 5}},
+		{"every 2 days until 2026-03-01 for 3 times", &models.Recurrence{Frequency: models.Daily, Interval: 2, Until: "2026-03-01", Count: 3}},
+	}
+
+	p := fixedParser(testNow)
+	for _, tt := range tests {
+		got, err := p.ParseRecurrence(tt.input)
+		if err != nil {
+			t.Errorf("ParseRecurrence(%q): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRecurrence(%q) = %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		token  string
+		offset int
+		reason string
+	}{
+		{"hourly", "hourly", 0, "unknown repeat rule"},
+		{"every", "", 5, "expected day"},
+		{"every 0 days", "0", 6, "the interval must be at least 1"},
+		{"every 3 fortnights", "fortnights", 8, "expected days, weeks or months"},
+		{"weekly on funday", "funday", 10, "unknown weekday"},
+		{"monthly on 32", "32", 11, "day 32 is not a valid day of the month"},
+		{"monthly on 6th mon", "6th", 11, "expected 1st to 5th"},
+		{"monthly on last", "", 15, "expected a weekday"},
+		{"daily until", "", 11, `expected a date after "until"`},
+		{"daily until someday", "someday", 12, "unknown date"},
+		{"until friday", "until", 0, "expected a repeat rule before"},
+		{"daily for 0 times", "0", 10, "expected a number of times"},
+		{"daily x0", "x0", 6, "the number of times must be at least 1"},
+		{"x3", "", 2, "expected a repeat rule before the number of times"},
+		{"daily please", "please", 6, "unexpected text"},
+	}
+
+	p := fixedParser(testNow)
+	for _, tt := range tests {
+		_, err := p.ParseRecurrence(tt.input)
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Errorf("ParseRecurrence(%q) error = %v, want a ParseError", tt.input, err)
+			continue
+		}
+		if perr.Input != tt.input || perr.Token != tt.token || perr.Offset != tt.offset {
+			t.Errorf("ParseRecurrence(%q) failed at %q (offset %d), want %q (offset %d)", tt.input, perr.Token, perr.Offset, tt.token, tt.offset)
+		}
+		if !strings.Contains(perr.Reason, tt.reason) {
+			t.Errorf("ParseRecurrence(%q) reason = %q, want it to contain %q", tt.input, perr.Reason, tt.reason)
+		}
+		if !strings.HasPrefix(perr.Error(), "cannot parse repeat rule ") {
+			t.Errorf("ParseRecurrence(%q) error = %q", tt.input, perr.Error())
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// MaxCarryOverEntries caps how many carried todos the carry-over log keeps
+const MaxCarryOverEntries = 500
+
+// CarriedTodo records an unfinished todo that was moved from a past day
+type CarriedTodo struct {
+	ID    string    `json:"id"`
+	Title string    `json:"title"`
+	From  string    `json:"from"` // date it was due, YYYY-MM-DD
+	To    string    `json:"to"`   // date it was carried to, YYYY-MM-DD
+	At    time.Time `json:"at"`
+}
+
+// CarryOverLog is the stored record of carried todos, oldest first
+type CarryOverLog struct {
+	Version   int           `json:"version"`
+	CheckedOn string        `json:"checked_on,omitempty"` // last day the carry-over policy ran
+	Entries   []CarriedTodo `json:"entries"`
+}
+
+// Record appends carried todos to the log, dropping the oldest entries
+// beyond MaxCarryOverEntries
+func (l *CarryOverLog) Record(entries ...CarriedTodo) {
+	l.Entries = append(l.Entries, entries...)
+	if extra := len(l.Entries) - MaxCarryOverEntries; extra > 0 {
+		l.Entries = append([]CarriedTodo(nil), l.Entries[extra:]...)
+	}
+}
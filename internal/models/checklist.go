@@ -0,0 +1,53 @@
+package models
+
+// ChecklistItem is a step inside a todo with its own completion state
+type ChecklistItem struct {
+	ID    string `json:"id,omitempty"` // set once the item belongs to a recurring series
+	Title string `json:"title"`
+	Done  bool   `json:"done"`
+}
+
+// AddChecklistItem appends an open item to the todo's checklist
+func (t *Todo) AddChecklistItem(title string) {
+	t.Checklist = append(t.Checklist, ChecklistItem{Title: title})
+}
+
+// RemoveChecklistItem deletes the item at index i, ignoring bad indexes
+func (t *Todo) RemoveChecklistItem(i int) {
+	if i < 0 || i >= len(t.Checklist) {
+		return
+	}
+	t.Checklist = append(t.Checklist[:i:i], t.Checklist[i+1:]...)
+}
+
+// ToggleChecklistItem flips the completion of the item at index i
+func (t *Todo) ToggleChecklistItem(i int) {
+	if i < 0 || i >= len(t.Checklist) {
+		return
+	}
+	t.Checklist[i].Done = !t.Checklist[i].Done
+}
+
+// ChecklistProgress returns how many checklist items are done and how many
+// there are in total
+func (t *Todo) ChecklistProgress() (done, total int) {
+	for _, item := range t.Checklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(t.Checklist)
+}
+
+// HasOpenChecklistItems reports whether any checklist item is not done yet
+func (t *Todo) HasOpenChecklistItems() bool {
+	done, total := t.ChecklistProgress()
+	return done < total
+}
+
+// CompleteChecklist marks every checklist item as done
+func (t *Todo) CompleteChecklist() {
+	for i := range t.Checklist {
+		t.Checklist[i].Done = true
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+// IsBlockedBy reports whether the todo declares id as one of its blockers
+func (t *Todo) IsBlockedBy(id string) bool {
+	return containsString(t.BlockedBy, id)
+}
+
+// ToggleBlocker adds id to the todo's blockers, or removes it if it is
+// already one of them
+func (t *Todo) ToggleBlocker(id string) {
+	for i, blocker := range t.BlockedBy {
+		if blocker == id {
+			t.BlockedBy = append(t.BlockedBy[:i:i], t.BlockedBy[i+1:]...)
+			return
+		}
+	}
+	t.BlockedBy = append(t.BlockedBy, id)
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// MaxHistoryEntries caps how many actions the undo history keeps
+const MaxHistoryEntries = 100
+
+// TodoChange is one todo as it was before and after an action, in the file
+// it is stored in. A nil Before means the action added the todo and a nil
+// After that it removed it.
+type TodoChange struct {
+	Date   *string `json:"date,omitempty"`   // day file the todo is stored in
+	Bucket string  `json:"bucket,omitempty"` // named bucket of undated todos and series
+	Before *Todo   `json:"before,omitempty"`
+	After  *Todo   `json:"after,omitempty"`
+}
+
+// ID returns the ID of the changed todo
+func (c TodoChange) ID() string {
+	if c.After != nil {
+		return c.After.ID
+	}
+	return c.Before.ID
+}
+
+// HistoryEntry is one undoable action along with every todo it changed
+type HistoryEntry struct {
+	Session string       `json:"session"` // when the tedo session that made it started
+	At      time.Time    `json:"at"`
+	Changes []TodoChange `json:"changes"`
+}
+
+// Describe summarizes the action, e.g. `delete "Buy milk"` or `change 3 todos`
+func (e HistoryEntry) Describe() string {
+	// A todo moved to another file is removed from one and added to the other
+	var before, after *Todo
+	ids := make(map[string]bool)
+	for _, change := range e.Changes {
+		ids[change.ID()] = true
+		if change.Before != nil {
+			before = change.Before
+		}
+		if change.After != nil {
+			after = change.After
+		}
+	}
+
+	switch {
+	case len(ids) != 1:
+		return fmt.Sprintf("change %d todos", len(ids))
+	case before == nil:
+		return fmt.Sprintf("add %q", after.Title)
+	case after == nil && before.DeletedAt != nil:
+		return fmt.Sprintf("purge %q", before.Title)
+	case after == nil:
+		return fmt.Sprintf("delete %q", before.Title)
+	case after.DeletedAt != nil && before.DeletedAt == nil:
+		return fmt.Sprintf("delete %q", after.Title)
+	case before.DeletedAt != nil && after.DeletedAt == nil:
+		return fmt.Sprintf("restore %q", after.Title)
+	case len(e.Changes) > 1:
+		return fmt.Sprintf("move %q", after.Title)
+	case len(after.SkippedOn) > len(before.SkippedOn):
+		return fmt.Sprintf("skip %q on %s", after.Title, after.SkippedOn[len(after.SkippedOn)-1])
+	case before.CurrentStatus() != after.CurrentStatus():
+		return fmt.Sprintf("mark %q %s", after.Title, after.CurrentStatus())
+	default:
+		return fmt.Sprintf("edit %q", after.Title)
+	}
+}
+
+// History is the stored undo and redo stacks, most recent action last
+type History struct {
+	Version int            `json:"version"`
+	Undo    []HistoryEntry `json:"undo"`
+	Redo    []HistoryEntry `json:"redo,omitempty"`
+}
+
+// Record pushes a new action onto the undo stack. The actions its session
+// undid can no longer be redone, and the oldest entries beyond
+// MaxHistoryEntries are dropped.
+func (h *History) Record(entry HistoryEntry) {
+	var redo []HistoryEntry
+	for _, undone := range h.Redo {
+		if undone.Session != entry.Session {
+			redo = append(redo, undone)
+		}
+	}
+	h.Redo = redo
+	h.Undo = append(h.Undo, entry)
+	if extra := len(h.Undo) - MaxHistoryEntries; extra > 0 {
+		h.Undo = append([]HistoryEntry(nil), h.Undo[extra:]...)
+	}
+}
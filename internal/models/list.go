@@ -0,0 +1,82 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DefaultListID is the list undated todos are filed in unless another is chosen
+const DefaultListID = "general"
+
+// List is a named collection of undated todos, such as a project
+type List struct {
+	ID        string    `json:"id"` // slug of the name, also the file name
+	Name      string    `json:"name"`
+	Archived  bool      `json:"archived,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ListIndex is the stored form of the list metadata
+type ListIndex struct {
+	Version int    `json:"version"`
+	Lists   []List `json:"lists"`
+}
+
+// NewList creates a list called name with the given ID
+func NewList(id, name string) List {
+	return List{
+		ID:        id,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+}
+
+// DefaultList returns the list that replaces the old general todos
+func DefaultList() List {
+	return NewList(DefaultListID, "General")
+}
+
+// IsDefault reports whether l is the default list
+func (l *List) IsDefault() bool {
+	return l.ID == DefaultListID
+}
+
+// ListSlug turns a list name into an ID usable as a file name, e.g.
+// "Backend Refactor!" becomes "backend-refactor"
+func ListSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "list"
+	}
+	return slug
+}
+
+// ListID returns the list the todo is filed in when it has no date
+func (t *Todo) ListID() string {
+	if t.List == "" {
+		return DefaultListID
+	}
+	return t.List
+}
+
+// SetList files an undated todo in the list with the given ID. The default
+// list is stored as an empty ID so older data stays unchanged.
+func (t *Todo) SetList(id string) {
+	if id == DefaultListID {
+		id = ""
+	}
+	t.List = id
+}
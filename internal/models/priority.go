@@ -0,0 +1,89 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Priority ranks how important a todo is. The zero value means no priority.
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+// String returns the lowercase name of the priority
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityMedium:
+		return "medium"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "none"
+	}
+}
+
+// Next returns the priority after p, wrapping from high back to none
+func (p Priority) Next() Priority {
+	if p >= PriorityHigh {
+		return PriorityNone
+	}
+	return p + 1
+}
+
+// ParsePriority parses a priority name, abbreviation or number (0-3)
+func ParsePriority(s string) (Priority, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "none", "0", "-":
+		return PriorityNone, nil
+	case "low", "l", "1", "!":
+		return PriorityLow, nil
+	case "medium", "med", "m", "2", "!!":
+		return PriorityMedium, nil
+	case "high", "h", "3", "!!!":
+		return PriorityHigh, nil
+	}
+	return PriorityNone, fmt.Errorf("unknown priority %q, expected none, low, medium or high", s)
+}
+
+// MarshalText stores priorities by name so data files stay readable
+func (p Priority) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText parses a priority stored by MarshalText
+func (p *Priority) UnmarshalText(text []byte) error {
+	parsed, err := ParsePriority(string(text))
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
+
+// SortTodos orders todos by date (general todos first) and then by
+// priority, highest first. Todos that compare equal keep their order.
+func SortTodos(todos []Todo) {
+	sort.SliceStable(todos, func(i, j int) bool {
+		a, b := todos[i], todos[j]
+		if dateA, dateB := dateKey(a), dateKey(b); dateA != dateB {
+			return dateA < dateB
+		}
+		return a.Priority > b.Priority
+	})
+}
+
+// dateKey sorts general todos before dated ones
+func dateKey(t Todo) string {
+	if t.Date == nil {
+		return ""
+	}
+	return *t.Date
+}
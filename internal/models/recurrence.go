@@ -0,0 +1,508 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Frequency is the base unit a recurrence repeats by
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+)
+
+// LastWeek selects the last matching weekday of a month in Recurrence.Week
+const LastWeek = -1
+
+// Recurrence describes how a recurring todo repeats. The todo's Date is the
+// first possible occurrence.
+type Recurrence struct {
+	Frequency Frequency      `json:"frequency"`
+	Interval  int            `json:"interval,omitempty"`  // every N days/weeks/months, 0 means 1
+	Weekdays  []time.Weekday `json:"weekdays,omitempty"`  // weekly days, or the weekday for Week
+	MonthDay  int            `json:"month_day,omitempty"` // monthly day, 0 means the start day
+	Week      int            `json:"week,omitempty"`      // monthly nth weekday (1-5 or LastWeek)
+	Until     string         `json:"until,omitempty"`     // last possible date, YYYY-MM-DD
+	Count     int            `json:"count,omitempty"`     // total number of occurrences
+}
+
+// interval returns the effective repeat interval
+func (r *Recurrence) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+// Matches reports whether the rule produces an occurrence on date for a
+// series starting on start. It ignores Count, see Todo.OccursOn.
+func (r *Recurrence) Matches(start, date time.Time) bool {
+	if date.Before(start) {
+		return false
+	}
+	if r.Until != "" && FormatDate(date) > r.Until {
+		return false
+	}
+
+	switch r.Frequency {
+	case Daily:
+		return daysBetween(start, date)%r.interval() == 0
+
+	case Weekly:
+		weekdays := r.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{start.Weekday()}
+		}
+		if !containsWeekday(weekdays, date.Weekday()) {
+			return false
+		}
+		weeks := daysBetween(startOfWeek(start), startOfWeek(date)) / 7
+		return weeks%r.interval() == 0
+
+	case Monthly:
+		months := (date.Year()-start.Year())*12 + int(date.Month()-start.Month())
+		if months%r.interval() != 0 {
+			return false
+		}
+
+		daysInMonth := GetDaysInMonth(date.Year(), date.Month())
+		if r.Week != 0 && len(r.Weekdays) > 0 {
+			if date.Weekday() != r.Weekdays[0] {
+				return false
+			}
+			if r.Week == LastWeek {
+				return date.Day()+7 > daysInMonth
+			}
+			return (date.Day()-1)/7+1 == r.Week
+		}
+
+		day := r.MonthDay
+		if day == 0 {
+			day = start.Day()
+		}
+		if day > daysInMonth {
+			day = daysInMonth
+		}
+		return date.Day() == day
+	}
+
+	return false
+}
+
+// Validate checks that the rule is complete and consistent
+func (r *Recurrence) Validate() error {
+	switch r.Frequency {
+	case Daily, Weekly, Monthly:
+	default:
+		return fmt.Errorf("unknown frequency %q", r.Frequency)
+	}
+
+	if r.Interval < 0 || r.Count < 0 {
+		return fmt.Errorf("interval and count cannot be negative")
+	}
+	if r.MonthDay < 0 || r.MonthDay > 31 {
+		return fmt.Errorf("day of month must be between 1 and 31")
+	}
+	if r.Week != 0 && (r.Week < LastWeek || r.Week > 5 || len(r.Weekdays) != 1) {
+		return fmt.Errorf("nth weekday rules need one weekday and a week between 1 and 5 or last")
+	}
+	if r.Until != "" {
+		if _, err := ParseDate(r.Until); err != nil {
+			return fmt.Errorf("invalid end date %q", r.Until)
+		}
+	}
+	return nil
+}
+
+// String describes the rule in the same syntax the rule parser accepts
+func (r *Recurrence) String() string {
+	var b strings.Builder
+
+	interval := r.interval()
+	switch r.Frequency {
+	case Daily:
+		if interval == 1 {
+			b.WriteString("daily")
+		} else {
+			fmt.Fprintf(&b, "every %d days", interval)
+		}
+
+	case Weekly:
+		if isWorkweek(r.Weekdays) && interval == 1 {
+			b.WriteString("weekdays")
+			break
+		}
+		if interval == 1 {
+			b.WriteString("weekly")
+		} else {
+			fmt.Fprintf(&b, "every %d weeks", interval)
+		}
+		if len(r.Weekdays) > 0 {
+			b.WriteString(" on " + formatWeekdays(r.Weekdays))
+		}
+
+	case Monthly:
+		if interval == 1 {
+			b.WriteString("monthly")
+		} else {
+			fmt.Fprintf(&b, "every %d months", interval)
+		}
+		switch {
+		case r.Week == LastWeek && len(r.Weekdays) > 0:
+			b.WriteString(" on last " + shortWeekday(r.Weekdays[0]))
+		case r.Week > 0 && len(r.Weekdays) > 0:
+			fmt.Fprintf(&b, " on %s %s", ordinal(r.Week), shortWeekday(r.Weekdays[0]))
+		case r.MonthDay > 0:
+			fmt.Fprintf(&b, " on %d", r.MonthDay)
+		}
+
+	default:
+		b.WriteString(string(r.Frequency))
+	}
+
+	if r.Until != "" {
+		b.WriteString(" until " + r.Until)
+	}
+	if r.Count > 0 {
+		fmt.Fprintf(&b, " for %d times", r.Count)
+	}
+	return b.String()
+}
+
+// IsRecurring returns true if the todo is a recurring series
+func (t *Todo) IsRecurring() bool {
+	return t.Recurrence != nil
+}
+
+// IsOccurrence returns true if the todo is one occurrence of a recurring series
+func (t *Todo) IsOccurrence() bool {
+	return t.SeriesID != ""
+}
+
+// OccursOn reports whether a recurring todo has an occurrence on date,
+// taking its start date, rule, skipped dates and occurrence count into account
+func (t *Todo) OccursOn(date string) bool {
+	return t.occursOn(date, t.lastOccurrence())
+}
+
+// occursOn is OccursOn for a series whose last occurrence, as returned by
+// lastOccurrence, is last
+func (t *Todo) occursOn(date, last string) bool {
+	if t.Recurrence == nil || t.Date == nil || containsString(t.SkippedOn, date) {
+		return false
+	}
+	if last != "" && date > last {
+		return false
+	}
+
+	start, err := ParseDate(*t.Date)
+	if err != nil {
+		return false
+	}
+	day, err := ParseDate(date)
+	return err == nil && t.Recurrence.Matches(start, day)
+}
+
+// lastOccurrence returns the date of the last occurrence of a recurring todo
+// limited by Count, or "" when the count does not end it
+func (t *Todo) lastOccurrence() string {
+	if t.Recurrence == nil || t.Recurrence.Count == 0 || t.Date == nil {
+		return ""
+	}
+	start, err := ParseDate(*t.Date)
+	if err != nil {
+		return ""
+	}
+	if last, ok := t.Recurrence.nthOccurrence(start, t.Recurrence.Count); ok {
+		return FormatDate(last)
+	}
+	return ""
+}
+
+// maxPeriodsPerOccurrence bounds how many periods nthOccurrence looks
+// through for each occurrence; an nth weekday rule can miss some months
+const maxPeriodsPerOccurrence = 12
+
+// nthOccurrence returns the date of the nth occurrence of a series starting
+// on start. It steps through the rule one period at a time, and reports
+// false when the series ends through Until first.
+func (r *Recurrence) nthOccurrence(start time.Time, n int) (time.Time, bool) {
+	if r.Frequency == Daily {
+		return start.AddDate(0, 0, (n-1)*r.interval()), true
+	}
+
+	seen := 0
+	for period := 0; period < n*maxPeriodsPerOccurrence; period += r.interval() {
+		var first time.Time
+		var days int
+		switch r.Frequency {
+		case Weekly:
+			first, days = startOfWeek(start).AddDate(0, 0, 7*period), 7
+		case Monthly:
+			first = time.Date(start.Year(), start.Month()+time.Month(period), 1, 0, 0, 0, 0, time.UTC)
+			days = GetDaysInMonth(first.Year(), first.Month())
+		default:
+			return time.Time{}, false
+		}
+
+		for i := 0; i < days; i++ {
+			d := first.AddDate(0, 0, i)
+			if r.Until != "" && FormatDate(d) > r.Until {
+				return time.Time{}, false
+			}
+			if r.Matches(start, d) {
+				if seen++; seen == n {
+					return d, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// OccurrencesBetween returns the dates after from and up to and including to
+// on which a recurring todo occurs
+func (t *Todo) OccurrencesBetween(from, to string) []string {
+	first, err := ParseDate(from)
+	if err != nil {
+		return nil
+	}
+	last, err := ParseDate(to)
+	if err != nil {
+		return nil
+	}
+
+	var dates []string
+	end := t.lastOccurrence()
+	for d := first.AddDate(0, 0, 1); !d.After(last); d = d.AddDate(0, 0, 1) {
+		if date := FormatDate(d); t.occursOn(date, end) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// maxOccurrenceSearchDays bounds how far ahead NextOccurrenceAfter looks
+const maxOccurrenceSearchDays = 2 * 366
+
+// NextOccurrenceAfter returns the first date after date on which a recurring
+// todo occurs, looking up to two years ahead
+func (t *Todo) NextOccurrenceAfter(date string) (string, bool) {
+	day, err := ParseDate(date)
+	if err != nil || t.Recurrence == nil {
+		return "", false
+	}
+
+	last := t.lastOccurrence()
+	for i := 1; i <= maxOccurrenceSearchDays; i++ {
+		if next := FormatDate(day.AddDate(0, 0, i)); t.occursOn(next, last) {
+			return next, true
+		}
+	}
+	return "", false
+}
+
+// Occurrence returns the instance of a recurring todo for date. Its
+// completion state is tracked separately from the rest of the series.
+func (t *Todo) Occurrence(date string) Todo {
+	occurrence := t.Clone()
+	occurrence.ID = OccurrenceID(t.ID, date)
+	occurrence.SeriesID = t.ID
+	occurrence.Date = &date
+	occurrence.Completed = containsString(t.CompletedOn, date)
+	occurrence.Status = t.StatusOn[date]
+	occurrence.NormalizeStatus()
+	occurrence.CompletedAt = nil
+	occurrence.CompletedOn = nil
+	occurrence.SkippedOn = nil
+	occurrence.StatusOn = nil
+
+	// Each occurrence works through its own copy of the checklist
+	for i := range occurrence.Checklist {
+		item := occurrence.Checklist[i]
+		occurrence.Checklist[i].Done = item.ID != "" && containsString(t.ChecklistDoneOn[date], item.ID)
+	}
+	occurrence.ChecklistDoneOn = nil
+	return occurrence
+}
+
+// SetOccurrenceChecklist saves the checklist of the occurrence on date. The
+// items become the series' checklist; which of them are done is kept for
+// that date only, by item ID, so it survives items being removed or moved.
+func (t *Todo) SetOccurrenceChecklist(date string, items []ChecklistItem) {
+	var done []string
+	t.Checklist = make([]ChecklistItem, len(items))
+	for i, item := range items {
+		if item.ID == "" {
+			item.ID = generateID()
+		}
+		t.Checklist[i] = ChecklistItem{ID: item.ID, Title: item.Title}
+		if item.Done {
+			done = append(done, item.ID)
+		}
+	}
+
+	// Forget removed items on the other dates too
+	for d, ids := range t.ChecklistDoneOn {
+		var kept []string
+		for _, id := range ids {
+			if t.checklistItem(id) >= 0 {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) == 0 {
+			delete(t.ChecklistDoneOn, d)
+		} else {
+			t.ChecklistDoneOn[d] = kept
+		}
+	}
+
+	if len(done) == 0 {
+		delete(t.ChecklistDoneOn, date)
+		return
+	}
+	if t.ChecklistDoneOn == nil {
+		t.ChecklistDoneOn = make(map[string][]string)
+	}
+	t.ChecklistDoneOn[date] = done
+}
+
+// checklistItem returns the index of the checklist item with the given ID,
+// or -1
+func (t *Todo) checklistItem(id string) int {
+	for i, item := range t.Checklist {
+		if item.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// SkipOccurrence removes the occurrence of a series on date, dropping the
+// state kept for it, while the rest of the series carries on
+func (t *Todo) SkipOccurrence(date string) {
+	t.SetOccurrenceStatus(date, StatusOpen)
+	delete(t.ChecklistDoneOn, date)
+	if !containsString(t.SkippedOn, date) {
+		t.SkippedOn = append(t.SkippedOn, date)
+	}
+}
+
+// SetOccurrenceCompleted marks the occurrence of a series on date as done or not done
+func (t *Todo) SetOccurrenceCompleted(date string, completed bool) {
+	t.CompletedOn = removeString(t.CompletedOn, date)
+	if completed {
+		t.CompletedOn = append(t.CompletedOn, date)
+	}
+}
+
+// SetOccurrenceStatus moves the occurrence of a series on date to status.
+// Done and open follow from CompletedOn; other statuses are kept by date.
+func (t *Todo) SetOccurrenceStatus(date string, status Status) {
+	t.SetOccurrenceCompleted(date, status.IsClosed())
+
+	if status == StatusOpen || status == StatusDone || status == "" {
+		delete(t.StatusOn, date)
+		return
+	}
+	if t.StatusOn == nil {
+		t.StatusOn = make(map[string]Status)
+	}
+	t.StatusOn[date] = status
+}
+
+// OccurrenceID builds the ID of the occurrence of a series on date
+func OccurrenceID(seriesID, date string) string {
+	return seriesID + "@" + date
+}
+
+// SplitOccurrenceID splits an occurrence ID into its series ID and date
+func SplitOccurrenceID(id string) (seriesID, date string, ok bool) {
+	seriesID, date, ok = strings.Cut(id, "@")
+	return seriesID, date, ok
+}
+
+// daysBetween returns the number of whole days from a to b
+func daysBetween(a, b time.Time) int {
+	a = time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	b = time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// startOfWeek returns the Sunday on or before date
+func startOfWeek(date time.Time) time.Time {
+	return date.AddDate(0, 0, -int(date.Weekday()))
+}
+
+// isWorkweek reports whether weekdays is exactly Monday to Friday
+func isWorkweek(weekdays []time.Weekday) bool {
+	if len(weekdays) != 5 {
+		return false
+	}
+	for day := time.Monday; day <= time.Friday; day++ {
+		if !containsWeekday(weekdays, day) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsWeekday(weekdays []time.Weekday, day time.Weekday) bool {
+	for _, d := range weekdays {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	var kept []string
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
+
+// shortWeekday returns the three letter lowercase name of a weekday
+func shortWeekday(day time.Weekday) string {
+	return strings.ToLower(day.String()[:3])
+}
+
+// formatWeekdays joins weekdays as "mon,wed,fri"
+func formatWeekdays(weekdays []time.Weekday) string {
+	names := make([]string, len(weekdays))
+	for i, day := range weekdays {
+		names[i] = shortWeekday(day)
+	}
+	return strings.Join(names, ",")
+}
+
+// ordinal formats 1 as "1st", 2 as "2nd" and so on
+func ordinal(n int) string {
+	switch n {
+	case 1:
+		return "1st"
+	case 2:
+		return "2nd"
+	case 3:
+		return "3rd"
+	default:
+		return fmt.Sprintf("%dth", n)
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// series returns a recurring todo starting on start
+func series(start string, rule Recurrence) Todo {
+	return Todo{ID: "s", Title: "Standup", Date: &start, Recurrence: &rule}
+}
+
+func TestOccurrencesBetween(t *testing.T) {
+	fridays := []time.Weekday{time.Friday}
+	tests := []struct {
+		name     string
+		todo     Todo
+		from, to string
+		want     string // occurrence dates, space separated
+	}{
+		{
+			name: "count counts skipped dates",
+			todo: func() Todo {
+				todo := series("2025-06-01", Recurrence{Frequency: Daily, Count: 3})
+				todo.SkippedOn = []string{"2025-06-02"}
+				return todo
+			}(),
+			from: "2025-05-31", to: "2025-06-10",
+			want: "2025-06-01 2025-06-03",
+		},
+		{
+			name: "weekly count counts skipped dates",
+			todo: func() Todo {
+				todo := series("2025-06-02", Recurrence{Frequency: Weekly, Weekdays: []time.Weekday{time.Monday, time.Wednesday}, Count: 4})
+				todo.SkippedOn = []string{"2025-06-04"}
+				return todo
+			}(),
+			from: "2025-06-01", to: "2025-06-30",
+			want: "2025-06-02 2025-06-09 2025-06-11",
+		},
+		{
+			name: "count of every other day",
+			todo: series("2025-06-01", Recurrence{Frequency: Daily, Interval: 2, Count: 3}),
+			from: "2025-05-31", to: "2025-06-30",
+			want: "2025-06-01 2025-06-03 2025-06-05",
+		},
+		{
+			name: "until before the start",
+			todo: series("2025-06-10", Recurrence{Frequency: Daily, Until: "2025-06-05"}),
+			from: "2025-06-01", to: "2025-06-30",
+			want: "",
+		},
+		{
+			name: "until before the start with a count",
+			todo: series("2025-06-10", Recurrence{Frequency: Monthly, Until: "2025-06-05", Count: 2}),
+			from: "2025-06-01", to: "2025-12-31",
+			want: "",
+		},
+		{
+			name: "until ends the series before the count",
+			todo: series("2025-06-01", Recurrence{Frequency: Weekly, Until: "2025-06-15", Count: 10}),
+			from: "2025-05-31", to: "2025-07-31",
+			want: "2025-06-01 2025-06-08 2025-06-15",
+		},
+		{
+			name: "fifth friday skips months without one",
+			todo: series("2025-01-01", Recurrence{Frequency: Monthly, Week: 5, Weekdays: fridays}),
+			from: "2024-12-31", to: "2025-12-31",
+			want: "2025-01-31 2025-05-30 2025-08-29 2025-10-31",
+		},
+		{
+			name: "count of fifth fridays",
+			todo: series("2025-01-01", Recurrence{Frequency: Monthly, Week: 5, Weekdays: fridays, Count: 3}),
+			from: "2024-12-31", to: "2026-12-31",
+			want: "2025-01-31 2025-05-30 2025-08-29",
+		},
+		{
+			name: "last friday",
+			todo: series("2025-01-01", Recurrence{Frequency: Monthly, Week: LastWeek, Weekdays: fridays}),
+			from: "2025-01-31", to: "2025-04-30",
+			want: "2025-02-28 2025-03-28 2025-04-25",
+		},
+		{
+			name: "day 31 falls on the last day of shorter months",
+			todo: series("2025-01-31", Recurrence{Frequency: Monthly, MonthDay: 31}),
+			from: "2025-01-30", to: "2025-05-31",
+			want: "2025-01-31 2025-02-28 2025-03-31 2025-04-30 2025-05-31",
+		},
+		{
+			name: "start day 31 in a leap year",
+			todo: series("2024-01-31", Recurrence{Frequency: Monthly}),
+			from: "2024-01-31", to: "2024-04-30",
+			want: "2024-02-29 2024-03-31 2024-04-30",
+		},
+		{
+			name: "count across month ends",
+			todo: series("2025-01-31", Recurrence{Frequency: Monthly, MonthDay: 31, Count: 3}),
+			from: "2025-01-30", to: "2025-12-31",
+			want: "2025-01-31 2025-02-28 2025-03-31",
+		},
+		{
+			name: "day 30 every other month",
+			todo: series("2025-12-30", Recurrence{Frequency: Monthly, Interval: 2, MonthDay: 30}),
+			from: "2025-12-29", to: "2026-06-30",
+			want: "2025-12-30 2026-02-28 2026-04-30 2026-06-30",
+		},
+	}
+
+	for _, tt := range tests {
+		got := strings.Join(tt.todo.OccurrencesBetween(tt.from, tt.to), " ")
+		if got != tt.want {
+			t.Errorf("%s: occurrences are %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNextOccurrenceAfterCount(t *testing.T) {
+	todo := series("2025-01-01", Recurrence{Frequency: Monthly, Week: 5, Weekdays: []time.Weekday{time.Friday}, Count: 2})
+	todo.SkippedOn = []string{"2025-01-31"}
+
+	if next, ok := todo.NextOccurrenceAfter("2025-01-01"); !ok || next != "2025-05-30" {
+		t.Errorf("next occurrence is %q, %v, want 2025-05-30", next, ok)
+	}
+	if next, ok := todo.NextOccurrenceAfter("2025-05-30"); ok {
+		t.Errorf("next occurrence after the last one is %q", next)
+	}
+	if todo.OccursOn("2025-08-29") {
+		t.Error("the series occurs after its count ran out")
+	}
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Status is where a todo is in its lifecycle. Data written before statuses
+// existed has none; its status follows from Completed.
+type Status string
+
+const (
+	StatusOpen       Status = "open"
+	StatusInProgress Status = "in-progress"
+	StatusWaiting    Status = "waiting"
+	StatusDone       Status = "done"
+	StatusCancelled  Status = "cancelled"
+)
+
+// Statuses lists every status in lifecycle order
+var Statuses = []Status{StatusOpen, StatusInProgress, StatusWaiting, StatusDone, StatusCancelled}
+
+// IsClosed reports whether nothing is left to do: the todo is done or
+// cancelled
+func (s Status) IsClosed() bool {
+	return s == StatusDone || s == StatusCancelled
+}
+
+// Next returns the status after s, wrapping from cancelled back to open
+func (s Status) Next() Status {
+	for i, status := range Statuses {
+		if status == s {
+			return Statuses[(i+1)%len(Statuses)]
+		}
+	}
+	return StatusInProgress
+}
+
+// ParseStatus parses a status name or a common abbreviation of it
+func ParseStatus(s string) (Status, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "open", "todo":
+		return StatusOpen, nil
+	case "in-progress", "in progress", "inprogress", "progress", "doing", "wip":
+		return StatusInProgress, nil
+	case "waiting", "wait", "on hold":
+		return StatusWaiting, nil
+	case "done", "completed", "complete":
+		return StatusDone, nil
+	case "cancelled", "canceled", "cancel":
+		return StatusCancelled, nil
+	}
+	return "", fmt.Errorf("unknown status %q, expected open, in-progress, waiting, done or cancelled", s)
+}
+
+// CurrentStatus returns the status of the todo, deriving it from Completed
+// for todos saved without one
+func (t *Todo) CurrentStatus() Status {
+	switch {
+	case t.Status == "" && t.Completed:
+		return StatusDone
+	case t.Status == "":
+		return StatusOpen
+	}
+	return t.Status
+}
+
+// SetStatus moves the todo to status. Completed stays in step: it is true
+// for done and cancelled todos, so older versions see them as finished.
+func (t *Todo) SetStatus(status Status) {
+	t.Status = status
+	t.Completed = status.IsClosed()
+}
+
+// NormalizeStatus reconciles Status with Completed after either was changed
+// on its own, e.g. by code or data that only knows about Completed
+func (t *Todo) NormalizeStatus() {
+	status := t.CurrentStatus()
+	switch {
+	case t.Completed && !status.IsClosed():
+		status = StatusDone
+	case !t.Completed && status.IsClosed():
+		status = StatusOpen
+	}
+	t.SetStatus(status)
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxTagLength is the longest tag accepted, not counting the leading #
+const MaxTagLength = 32
+
+// ParseTags parses a list of tags such as "#work #home" or "work, team/api".
+// Tags are lowercased, the leading # is optional and duplicates are dropped.
+func ParseTags(input string) ([]string, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	var tags []string
+	for _, field := range fields {
+		tag := strings.ToLower(strings.TrimLeft(field, "#"))
+		if tag == "" {
+			continue
+		}
+		if err := validateTag(tag); err != nil {
+			return nil, err
+		}
+		if !containsString(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// validateTag checks that a tag only uses letters, digits, '-', '_' and '/'
+func validateTag(tag string) error {
+	if len(tag) > MaxTagLength {
+		return fmt.Errorf("tag #%s is longer than %d characters", tag, MaxTagLength)
+	}
+	for _, r := range tag {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_/", r) {
+			return fmt.Errorf("invalid tag #%s: only letters, digits, '-', '_' and '/' are allowed", tag)
+		}
+	}
+	return nil
+}
+
+// FormatTags renders tags the way they are typed, e.g. "#work #home"
+func FormatTags(tags []string) string {
+	formatted := make([]string, len(tags))
+	for i, tag := range tags {
+		formatted[i] = "#" + tag
+	}
+	return strings.Join(formatted, " ")
+}
+
+// HasTag reports whether the todo carries tag
+func (t *Todo) HasTag(tag string) bool {
+	return containsString(t.Tags, strings.ToLower(strings.TrimPrefix(tag, "#")))
+}
+
+// HasTags reports whether the todo carries every one of tags
+func (t *Todo) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		if !t.HasTag(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterByTags returns the todos that carry every one of tags
+func FilterByTags(todos []Todo, tags []string) []Todo {
+	if len(tags) == 0 {
+		return todos
+	}
+
+	var filtered []Todo
+	for _, todo := range todos {
+		if todo.HasTags(tags) {
+			filtered = append(filtered, todo)
+		}
+	}
+	return filtered
+}
@@ -4,17 +4,50 @@ import "time"
 
 // Todo represents a single todo item
 type Todo struct {
-	ID          string    `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Completed   bool      `json:"completed"`
-	CreatedAt   time.Time `json:"created_at"`
-	Date        *string   `json:"data,omitempty"` // nil for general todos, YYYY-MM-DD
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Completed   bool       `json:"completed"`        // true once done or cancelled
+	Status      Status     `json:"status,omitempty"` // empty in data saved before statuses
+	Priority    Priority   `json:"priority,omitempty"`
+	Tags        []string   `json:"tags,omitempty"` // lowercase, without the leading #
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`   // set by the repository on every save
+	CompletedAt *time.Time `json:"completed_at,omitempty"` // when it was done or cancelled
+	Date        *string    `json:"date,omitempty"`         // nil for undated todos, YYYY-MM-DD
+	List        string     `json:"list,omitempty"`         // list of an undated todo, empty for the default list
+	CarriedFrom string     `json:"carried_from,omitempty"` // date it was due before being carried over
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`   // when it was moved to the trash
+
+	// Nested steps with their own completion state, in order
+	Checklist []ChecklistItem `json:"checklist,omitempty"`
+
+	// IDs of the todos that must be done first, on any date or list
+	BlockedBy []string `json:"blocked_by,omitempty"`
+
+	// Recurring series only; Date is the first possible occurrence
+	Recurrence  *Recurrence `json:"recurrence,omitempty"`
+	CompletedOn []string    `json:"completed_on,omitempty"` // dates of completed occurrences
+	SkippedOn   []string    `json:"skipped_on,omitempty"`   // dates whose occurrence was removed
+
+	// IDs of the checklist items done per occurrence date
+	ChecklistDoneOn map[string][]string `json:"checklist_done_on,omitempty"`
+
+	// Occurrences that are in progress, waiting or cancelled, by date
+	StatusOn map[string]Status `json:"status_on,omitempty"`
+
+	// Set on occurrences generated from a series. Occurrences are not saved,
+	// so it only shows up in JSON output such as tedo list -json.
+	SeriesID string `json:"series_id,omitempty"`
 }
 
+// CurrentVersion is the data file format version written by this build
+const CurrentVersion = 2
+
 // TodoList represents a collection of todos for a specific context
 type TodoList struct {
-	Todos []Todo `json:"todos"`
+	Version int    `json:"version"`
+	Todos   []Todo `json:"todos"`
 }
 
 // NewTodo creates a new todo with generated ID and current timestamp
@@ -24,6 +57,7 @@ func NewTodo(title, description string, date *string) Todo {
 		Title:       title,
 		Description: description,
 		Completed:   false,
+		Status:      StatusOpen,
 		CreatedAt:   time.Now(),
 		Date:        date,
 	}
@@ -39,7 +73,52 @@ func (t *Todo) IsForDate(date string) bool {
 	return t.Date != nil && *t.Date == date
 }
 
-// Toggle Switched the completion status of the todo
+// Toggle marks an unfinished todo as done, or reopens a done or cancelled one
 func (t *Todo) Toggle() {
-	t.Completed = !t.Completed
+	if t.Completed {
+		t.SetStatus(StatusOpen)
+	} else {
+		t.SetStatus(StatusDone)
+	}
+}
+
+// Clone returns a copy of the todo that shares no slices or pointers with it
+func (t Todo) Clone() Todo {
+	if t.Date != nil {
+		date := *t.Date
+		t.Date = &date
+	}
+	if t.Recurrence != nil {
+		recurrence := *t.Recurrence
+		recurrence.Weekdays = append([]time.Weekday(nil), recurrence.Weekdays...)
+		t.Recurrence = &recurrence
+	}
+	t.Tags = append([]string(nil), t.Tags...)
+	t.Checklist = append([]ChecklistItem(nil), t.Checklist...)
+	t.CompletedOn = append([]string(nil), t.CompletedOn...)
+	t.SkippedOn = append([]string(nil), t.SkippedOn...)
+	t.BlockedBy = append([]string(nil), t.BlockedBy...)
+	if t.UpdatedAt != nil {
+		updatedAt := *t.UpdatedAt
+		t.UpdatedAt = &updatedAt
+	}
+	if t.CompletedAt != nil {
+		completedAt := *t.CompletedAt
+		t.CompletedAt = &completedAt
+	}
+	if t.StatusOn != nil {
+		statusOn := make(map[string]Status, len(t.StatusOn))
+		for date, status := range t.StatusOn {
+			statusOn[date] = status
+		}
+		t.StatusOn = statusOn
+	}
+	if t.ChecklistDoneOn != nil {
+		doneOn := make(map[string][]string, len(t.ChecklistDoneOn))
+		for date, done := range t.ChecklistDoneOn {
+			doneOn[date] = append([]string(nil), done...)
+		}
+		t.ChecklistDoneOn = doneOn
+	}
+	return t
 }
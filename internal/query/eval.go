@@ -0,0 +1,160 @@
+package query
+
+import (
+	"strings"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// Env is what a query is evaluated against
+type Env struct {
+	Now   time.Time         // relative dates such as 7d count from this day
+	Lists map[string]string // list names by ID, so list: matches names too
+}
+
+// Matcher returns a function reporting whether a todo matches the query.
+// Relative dates are resolved once, against env.Now.
+func (q *Query) Matcher(env Env) (func(models.Todo) bool, error) {
+	if q.Root == nil {
+		return func(models.Todo) bool { return true }, nil
+	}
+	return compile(q.Root, env)
+}
+
+// Filter returns the todos that match the query
+func (q *Query) Filter(todos []models.Todo, env Env) ([]models.Todo, error) {
+	match, err := q.Matcher(env)
+	if err != nil {
+		return nil, err
+	}
+
+	var kept []models.Todo
+	for _, todo := range todos {
+		if match(todo) {
+			kept = append(kept, todo)
+		}
+	}
+	return kept, nil
+}
+
+// compile turns a node into a matcher
+func compile(node Node, env Env) (func(models.Todo) bool, error) {
+	switch n := node.(type) {
+	case And:
+		left, right, err := compilePair(n.Left, n.Right, env)
+		if err != nil {
+			return nil, err
+		}
+		return func(t models.Todo) bool { return left(t) && right(t) }, nil
+
+	case Or:
+		left, right, err := compilePair(n.Left, n.Right, env)
+		if err != nil {
+			return nil, err
+		}
+		return func(t models.Todo) bool { return left(t) || right(t) }, nil
+
+	case Not:
+		x, err := compile(n.X, env)
+		if err != nil {
+			return nil, err
+		}
+		return func(t models.Todo) bool { return !x(t) }, nil
+
+	case Term:
+		return n.compile(env)
+	}
+	panic("query: unknown node")
+}
+
+// compilePair compiles both sides of a binary node
+func compilePair(left, right Node, env Env) (func(models.Todo) bool, func(models.Todo) bool, error) {
+	l, err := compile(left, env)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := compile(right, env)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, r, nil
+}
+
+// compile turns a term into a matcher
+func (t Term) compile(env Env) (func(models.Todo) bool, error) {
+	switch t.Field {
+	case FieldDue:
+		return t.compileDue(env)
+
+	case FieldPriority:
+		priority, err := models.ParsePriority(t.Value)
+		if err != nil {
+			return nil, err
+		}
+		return func(todo models.Todo) bool {
+			return compare(int(todo.Priority), int(priority), t.Op)
+		}, nil
+
+	case FieldStatus:
+		status, err := models.ParseStatus(t.Value)
+		if err != nil {
+			return nil, err
+		}
+		return func(todo models.Todo) bool { return todo.CurrentStatus() == status }, nil
+
+	case FieldTag:
+		return func(todo models.Todo) bool { return todo.HasTag(t.Value) }, nil
+
+	case FieldList:
+		value := strings.ToLower(t.Value)
+		return func(todo models.Todo) bool {
+			if todo.Date != nil {
+				return false
+			}
+			id := todo.ListID()
+			return id == value || strings.ToLower(env.Lists[id]) == value
+		}, nil
+	}
+
+	// Text, and words without a field
+	value := strings.ToLower(t.Value)
+	return func(todo models.Todo) bool {
+		return strings.Contains(strings.ToLower(todo.Title), value) ||
+			strings.Contains(strings.ToLower(todo.Description), value)
+	}, nil
+}
+
+// compileDue matches the date of a todo. Undated todos only match due:none.
+func (t Term) compileDue(env Env) (func(models.Todo) bool, error) {
+	switch strings.ToLower(t.Value) {
+	case "none":
+		return func(todo models.Todo) bool { return todo.Date == nil }, nil
+	case "any":
+		return func(todo models.Todo) bool { return todo.Date != nil }, nil
+	}
+
+	date, err := resolveDate(t.Value, env.Now)
+	if err != nil {
+		return nil, err
+	}
+	return func(todo models.Todo) bool {
+		return todo.Date != nil && compare(strings.Compare(*todo.Date, date), 0, t.Op)
+	}, nil
+}
+
+// compare applies op to a and b
+func compare(a, b int, op Op) bool {
+	switch op {
+	case OpLess:
+		return a < b
+	case OpLessEqual:
+		return a <= b
+	case OpGreater:
+		return a > b
+	case OpGreaterEqual:
+		return a >= b
+	default:
+		return a == b
+	}
+}
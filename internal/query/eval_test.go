@@ -0,0 +1,131 @@
+package query
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// testEnv evaluates queries on Sunday 2025-06-15
+var testEnv = Env{
+	Now:   time.Date(2025, 6, 15, 9, 30, 0, 0, time.Local),
+	Lists: map[string]string{"books-id": "Books"},
+}
+
+// testTodos returns todos covering every field, by ID
+func testTodos() []models.Todo {
+	date := func(s string) *string { return &s }
+	return []models.Todo{
+		{ID: "deploy", Title: "Deploy app", Date: date("2025-06-15"), Priority: models.PriorityHigh, Tags: []string{"work"}, Status: models.StatusOpen},
+		{ID: "report", Title: "Write report", Description: "Quarterly numbers", Date: date("2025-06-20"), Priority: models.PriorityMedium, Tags: []string{"work"}, Status: models.StatusInProgress},
+		{ID: "overdue", Title: "Renew passport", Date: date("2025-06-01"), Priority: models.PriorityLow},
+		{ID: "trip", Title: "Plan trip", Date: date("2025-07-30"), Priority: models.PriorityLow, Tags: []string{"home"}},
+		{ID: "milk", Title: "Buy milk", Completed: true},
+		{ID: "book", Title: "Read book", List: "books-id", Status: models.StatusWaiting},
+	}
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string // IDs of the matching todos, in order
+	}{
+		{"", "deploy report overdue trip milk book"},
+
+		// Relative and absolute dates, counted from testEnv.Now
+		{"due:<7d", "deploy report overdue"},
+		{"due<7d", "deploy report overdue"},
+		{"due<+7d", "deploy report overdue"},
+		{"due:>=1w", "trip"},
+		{"due:today", "deploy"},
+		{"due<today", "overdue"},
+		{"due:tomorrow", ""},
+		{"due<=5d", "deploy report overdue"},
+		{"due>+1m", "trip"},
+		{"due:2025-06-20", "report"},
+		{`due:"in 5 days"`, "report"},
+		{"due:none", "milk book"},
+		{"due:any", "deploy report overdue trip"},
+
+		{"prio>=high", "deploy"},
+		{"prio:>=medium", "deploy report"},
+		{"prio<medium", "overdue trip milk book"},
+		{"prio:low", "overdue trip"},
+		{"prio:none", "milk book"},
+
+		{"status:open", "deploy overdue trip"},
+		{"is:done", "milk"},
+		{"is:doing", "report"},
+		{"status:waiting", "book"},
+
+		{"tag:work", "deploy report"},
+		{"tag:#HOME", "trip"},
+
+		{"list:books", "book"},
+		{"list:books-id", "book"},
+		{"list:general", "milk"},
+
+		{"quarterly", "report"},
+		{"text:MILK", "milk"},
+		{`"write report"`, "report"},
+
+		// Negation, or and grouping
+		{"-text:deploy tag:work", "report"},
+		{"tag:work -deploy", "report"},
+		{"milk or trip", "trip milk"},
+		{"not (tag:work or tag:home)", "overdue milk book"},
+		{"-(tag:work or tag:home) due:any", "overdue"},
+		{"-tag:work prio>=low", "overdue trip"},
+		{"tag:work or due:none prio:none", "deploy report milk book"},
+		{"(tag:work or due:none) -is:done", "deploy report book"},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.query)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.query, err)
+			continue
+		}
+		todos, err := q.Filter(testTodos(), testEnv)
+		if err != nil {
+			t.Errorf("Filter(%q): %v", tt.query, err)
+			continue
+		}
+
+		var ids []string
+		for _, todo := range todos {
+			ids = append(ids, todo.ID)
+		}
+		if got := strings.Join(ids, " "); got != tt.want {
+			t.Errorf("Filter(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestMatcherResolvesDatesAgainstNow(t *testing.T) {
+	q, err := Parse("due:<7d")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	date := "2025-06-20"
+	todo := models.Todo{Title: "Write report", Date: &date}
+	for _, tt := range []struct {
+		now  time.Time
+		want bool
+	}{
+		{time.Date(2025, 6, 15, 0, 0, 0, 0, time.Local), true},
+		{time.Date(2025, 6, 13, 23, 59, 0, 0, time.Local), false},
+		{time.Date(2025, 6, 14, 0, 0, 0, 0, time.Local), true},
+	} {
+		match, err := q.Matcher(Env{Now: tt.now})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := match(todo); got != tt.want {
+			t.Errorf("due:<7d on %s matched %s = %v, want %v", tt.now.Format("2006-01-02"), date, got, tt.want)
+		}
+	}
+}
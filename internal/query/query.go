@@ -0,0 +1,448 @@
+// Package query parses and evaluates the query language used to select
+// todos, e.g. `due:<7d tag:work status:open prio>=high text:"deploy"`.
+//
+// A query is a list of terms that must all match. Terms can be joined with
+// "or", negated with "not" or a leading "-", and grouped with parentheses.
+// A word without a field matches the title or description.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/dateparse"
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// Field is the property of a todo a term looks at
+type Field string
+
+const (
+	FieldDue      Field = "due"
+	FieldTag      Field = "tag"
+	FieldStatus   Field = "status"
+	FieldPriority Field = "prio"
+	FieldText     Field = "text"
+	FieldList     Field = "list"
+)
+
+// fields maps the names a field can be written as to the field
+var fields = map[string]Field{
+	"due":      FieldDue,
+	"date":     FieldDue,
+	"tag":      FieldTag,
+	"tags":     FieldTag,
+	"status":   FieldStatus,
+	"is":       FieldStatus,
+	"prio":     FieldPriority,
+	"priority": FieldPriority,
+	"text":     FieldText,
+	"list":     FieldList,
+}
+
+// Op compares a field with the value of a term
+type Op string
+
+const (
+	OpEqual        Op = ":"
+	OpLess         Op = "<"
+	OpLessEqual    Op = "<="
+	OpGreater      Op = ">"
+	OpGreaterEqual Op = ">="
+)
+
+// Node is a node of a parsed query
+type Node interface {
+	String() string
+}
+
+// And matches todos matched by both sides
+type And struct {
+	Left, Right Node
+}
+
+// Or matches todos matched by either side
+type Or struct {
+	Left, Right Node
+}
+
+// Not matches todos its operand does not match
+type Not struct {
+	X Node
+}
+
+// Term compares one field of a todo with a value
+type Term struct {
+	Field  Field
+	Op     Op
+	Value  string
+	Offset int // byte offset of the term in the query
+}
+
+func (n And) String() string { return "(" + n.Left.String() + " and " + n.Right.String() + ")" }
+func (n Or) String() string  { return "(" + n.Left.String() + " or " + n.Right.String() + ")" }
+func (n Not) String() string { return "not " + n.X.String() }
+
+func (t Term) String() string {
+	value := t.Value
+	if value == "" || strings.ContainsAny(value, " \t()\"") {
+		value = fmt.Sprintf("%q", value)
+	}
+	if t.Op == OpEqual {
+		return string(t.Field) + ":" + value
+	}
+	return string(t.Field) + string(t.Op) + value
+}
+
+// Query is a parsed query
+type Query struct {
+	Input string
+	Root  Node // nil for an empty query, which matches everything
+}
+
+// String returns the query as it was written
+func (q *Query) String() string {
+	return q.Input
+}
+
+// Error describes which part of a query could not be parsed
+type Error struct {
+	Input  string // the full query
+	Token  string // the part that failed, empty if the query ended early
+	Offset int    // byte offset of Token in Input
+	Reason string
+}
+
+func (e *Error) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("cannot parse query %q: %s", e.Input, e.Reason)
+	}
+	return fmt.Sprintf("cannot parse query %q at %q: %s", e.Input, e.Token, e.Reason)
+}
+
+// Parse parses a query. Values are checked as they are parsed, so a query
+// that parses can always be evaluated.
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{input: input, tokens: tokens}
+	q := &Query{Input: input}
+	if len(tokens) == 0 {
+		return q, nil
+	}
+
+	if q.Root, err = p.parseOr(); err != nil {
+		return nil, err
+	}
+	if tok, ok := p.peek(); ok {
+		return nil, p.errorAt(tok, "unexpected "+describe(tok))
+	}
+	return q, nil
+}
+
+// tokenKind tells the parser how to treat a token
+type tokenKind int
+
+const (
+	tokenWord tokenKind = iota
+	tokenOpen
+	tokenClose
+	tokenNot // a leading "-"
+)
+
+// token is a word, parenthesis or negation of the query along with its
+// position
+type token struct {
+	kind   tokenKind
+	text   string // as written, including quotes
+	offset int
+	quoted bool // the word contains a quoted part
+}
+
+// tokenize splits a query into words and parentheses. A quoted part keeps
+// spaces and parentheses inside a word.
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		switch c := input[i]; {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", offset: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", offset: i})
+			i++
+		case c == '-' && i+1 < len(input) && !strings.ContainsRune(" \t)-", rune(input[i+1])):
+			tokens = append(tokens, token{kind: tokenNot, text: "-", offset: i})
+			i++
+		default:
+			start := i
+			quoted := false
+			for i < len(input) && !strings.ContainsRune(" \t()", rune(input[i])) {
+				if input[i] != '"' {
+					i++
+					continue
+				}
+				end := strings.IndexByte(input[i+1:], '"')
+				if end < 0 {
+					return nil, &Error{Input: input, Token: input[i:], Offset: i, Reason: "missing closing quote"}
+				}
+				quoted = true
+				i += end + 2
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: input[start:i], offset: start, quoted: quoted})
+		}
+	}
+	return tokens, nil
+}
+
+// parser walks the tokens of one query
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+// peek returns the current token without consuming it
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// next consumes and returns the current token
+func (p *parser) next() (token, bool) {
+	tok, ok := p.peek()
+	if ok {
+		p.pos++
+	}
+	return tok, ok
+}
+
+// isKeyword reports whether tok is the unquoted word keyword
+func isKeyword(tok token, keyword string) bool {
+	return tok.kind == tokenWord && !tok.quoted && strings.EqualFold(tok.text, keyword)
+}
+
+// parseOr parses terms joined by "or"
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok || !isKeyword(tok, "or") {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{Left: left, Right: right}
+	}
+}
+
+// parseAnd parses terms joined by "and" or written next to each other
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == tokenClose || isKeyword(tok, "or") {
+			return left, nil
+		}
+		if isKeyword(tok, "and") {
+			p.next()
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{Left: left, Right: right}
+	}
+}
+
+// parseUnary parses a negated term, a group in parentheses or a term
+func (p *parser) parseUnary() (Node, error) {
+	tok, ok := p.next()
+	if !ok {
+		return nil, p.errorAtEnd("expected a term")
+	}
+
+	switch {
+	case tok.kind == tokenNot || isKeyword(tok, "not"):
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return Not{X: x}, nil
+
+	case tok.kind == tokenOpen:
+		x, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing, ok := p.next(); !ok || closing.kind != tokenClose {
+			return nil, p.errorAtEnd("missing closing parenthesis")
+		}
+		return x, nil
+
+	case tok.kind == tokenClose:
+		return nil, p.errorAt(tok, "unexpected closing parenthesis")
+
+	case isKeyword(tok, "and") || isKeyword(tok, "or"):
+		return nil, p.errorAt(tok, fmt.Sprintf("expected a term before %q", tok.text))
+	}
+
+	return p.parseTerm(tok)
+}
+
+// parseTerm parses a word such as `due:<7d`, `prio>=high` or `deploy`
+func (p *parser) parseTerm(tok token) (Node, error) {
+	name, rest := splitField(tok.text)
+	if name == "" {
+		return Term{Field: FieldText, Op: OpEqual, Value: unquote(tok.text), Offset: tok.offset}, nil
+	}
+
+	field, ok := fields[strings.ToLower(name)]
+	if !ok {
+		return nil, p.errorAt(tok, fmt.Sprintf("unknown field %q, expected due, tag, status, prio, text or list", name))
+	}
+
+	op, value := splitOp(rest)
+	term := Term{Field: field, Op: op, Value: unquote(value), Offset: tok.offset}
+	if term.Value == "" {
+		return nil, p.errorAt(tok, fmt.Sprintf("missing value for %s", field))
+	}
+	if err := term.check(); err != nil {
+		return nil, p.errorAt(tok, err.Error())
+	}
+	return term, nil
+}
+
+// splitField splits a word into its field name and the operator and value
+// after it. Words without a field give an empty name.
+func splitField(word string) (name, rest string) {
+	for i, r := range word {
+		if r == ':' || r == '<' || r == '>' || r == '=' {
+			if i == 0 {
+				return "", word
+			}
+			return word[:i], word[i:]
+		}
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return "", word
+		}
+	}
+	return "", word
+}
+
+// splitOp splits the operator from the value of a term. `:`, `=` and no
+// comparison all mean equal; `:<` and `<` are the same.
+func splitOp(rest string) (Op, string) {
+	rest = strings.TrimPrefix(rest, ":")
+	for _, op := range []Op{OpLessEqual, OpGreaterEqual, OpLess, OpGreater} {
+		if strings.HasPrefix(rest, string(op)) {
+			return op, rest[len(op):]
+		}
+	}
+	return OpEqual, strings.TrimPrefix(rest, "=")
+}
+
+// unquote removes the quotes of the quoted parts of a word
+func unquote(word string) string {
+	return strings.ReplaceAll(word, `"`, "")
+}
+
+// check reports whether the term's operator and value suit its field
+func (t Term) check() error {
+	switch t.Field {
+	case FieldDue:
+		if isDateKeyword(t.Value) {
+			if t.Op != OpEqual {
+				return fmt.Errorf("%s:%s cannot be compared", t.Field, t.Value)
+			}
+			return nil
+		}
+		_, err := resolveDate(t.Value, time.Now())
+		return err
+
+	case FieldPriority:
+		_, err := models.ParsePriority(t.Value)
+		return err
+
+	case FieldStatus:
+		if t.Op != OpEqual {
+			return fmt.Errorf("status can only be matched with ':'")
+		}
+		_, err := models.ParseStatus(t.Value)
+		return err
+
+	case FieldTag, FieldText, FieldList:
+		if t.Op != OpEqual {
+			return fmt.Errorf("%s can only be matched with ':'", t.Field)
+		}
+	}
+	return nil
+}
+
+// isDateKeyword reports whether value is "none" or "any", which match
+// undated and dated todos
+func isDateKeyword(value string) bool {
+	switch strings.ToLower(value) {
+	case "none", "any":
+		return true
+	}
+	return false
+}
+
+// offsetPattern matches offsets written without a sign, such as 7d or 2w
+var offsetPattern = regexp.MustCompile(`^\d+(d|days?|w|wks?|weeks?|m|mo|months?|y|yrs?|years?)$`)
+
+// resolveDate resolves a due value against now. Offsets may leave out the
+// sign, so `7d` means the same as `+7d`.
+func resolveDate(value string, now time.Time) (string, error) {
+	if offsetPattern.MatchString(strings.ToLower(value)) {
+		value = "+" + value
+	}
+
+	date, err := dateparse.New(func() time.Time { return now }).Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return models.FormatDate(date), nil
+}
+
+// describe names a token in error messages
+func describe(tok token) string {
+	if tok.kind == tokenClose {
+		return "closing parenthesis"
+	}
+	return fmt.Sprintf("%q", tok.text)
+}
+
+// errorAt builds an Error pointing at tok
+func (p *parser) errorAt(tok token, reason string) error {
+	return &Error{Input: p.input, Token: tok.text, Offset: tok.offset, Reason: reason}
+}
+
+// errorAtEnd builds an Error for a query that ended too early
+func (p *parser) errorAtEnd(reason string) error {
+	return &Error{Input: p.input, Offset: len(p.input), Reason: reason}
+}
@@ -0,0 +1,117 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string // the parsed tree, empty for a query that matches everything
+	}{
+		{"", ""},
+		{"  ", ""},
+		{"deploy", "text:deploy"},
+		{`text:"deploy now"`, `text:"deploy now"`},
+		{`"deploy now"`, `text:"deploy now"`},
+		{"due:<7d", "due<7d"},
+		{"due<7d", "due<7d"},
+		{"due:<=2025-06-20", "due<=2025-06-20"},
+		{"due=today", "due:today"},
+		{"due:none", "due:none"},
+		{"prio>=high", "prio>=high"},
+		{"priority:h", "prio:h"},
+		{"is:done", "status:done"},
+		{"tags:work", "tag:work"},
+		{"list:Books", "list:Books"},
+		{"a-b", "text:a-b"},
+		{"- a", "(text:- and text:a)"},
+
+		// Negation
+		{"-text:deploy", "not text:deploy"},
+		{"-deploy", "not text:deploy"},
+		{"not tag:work", "not tag:work"},
+		{"-(a or b)", "not (text:a or text:b)"},
+		{"not not a", "not not text:a"},
+
+		// Terms next to each other bind tighter than or, negation tighter
+		// than both
+		{"tag:work status:open", "(tag:work and status:open)"},
+		{"a and b", "(text:a and text:b)"},
+		{"a b or c", "((text:a and text:b) or text:c)"},
+		{"a or b c", "(text:a or (text:b and text:c))"},
+		{"a OR b", "(text:a or text:b)"},
+		{"-a b", "(not text:a and text:b)"},
+		{"not a or b", "(not text:a or text:b)"},
+		{"a and not b", "(text:a and not text:b)"},
+		{"a (b or c)", "(text:a and (text:b or text:c))"},
+		{"(a or b) -c", "((text:a or text:b) and not text:c)"},
+		{`"or" b`, "(text:or and text:b)"},
+	}
+
+	for _, tt := range tests {
+		q, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+
+		got := ""
+		if q.Root != nil {
+			got = q.Root.String()
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+		if q.String() != tt.input {
+			t.Errorf("Parse(%q).String() = %q", tt.input, q.String())
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input  string
+		token  string
+		offset int
+		reason string
+	}{
+		{"-text:", "text:", 1, "missing value for text"},
+		{"tag:work text:", "text:", 9, "missing value for text"},
+		{`text:"deploy`, `"deploy`, 5, "missing closing quote"},
+		{`a "b c`, `"b c`, 2, "missing closing quote"},
+		{"foo:bar", "foo:bar", 0, `unknown field "foo"`},
+		{"a or", "", 4, "expected a term"},
+		{"a and", "", 5, "expected a term"},
+		{"not", "", 3, "expected a term"},
+		{"or a", "or", 0, `expected a term before "or"`},
+		{"a or or b", "or", 5, `expected a term before "or"`},
+		{"(a", "", 2, "missing closing parenthesis"},
+		{"(a or (b)", "", 9, "missing closing parenthesis"},
+		{"a )", ")", 2, "unexpected closing parenthesis"},
+		{")", ")", 0, "unexpected closing parenthesis"},
+		{"prio>=urgent", "prio>=urgent", 0, "unknown priority"},
+		{"status<open", "status<open", 0, "status can only be matched with ':'"},
+		{"is:finished", "is:finished", 0, "unknown status"},
+		{"tag>work", "tag>work", 0, "tag can only be matched with ':'"},
+		{"due<none", "due<none", 0, "due:none cannot be compared"},
+		{"a due:someday", "due:someday", 2, "cannot parse date"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.input)
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%q) error = %v, want a query error", tt.input, err)
+			continue
+		}
+		if perr.Input != tt.input || perr.Token != tt.token || perr.Offset != tt.offset {
+			t.Errorf("Parse(%q) failed at %q (offset %d), want %q (offset %d)", tt.input, perr.Token, perr.Offset, tt.token, tt.offset)
+		}
+		if !strings.Contains(perr.Reason, tt.reason) {
+			t.Errorf("Parse(%q) reason = %q, want it to contain %q", tt.input, perr.Reason, tt.reason)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/WasathTheekshana/tedo/internal/atomicfile"
+)
+
+const (
+	// BackupExt is appended to a data file to name its most recent backup
+	BackupExt = ".bak"
+
+	// BackupCount is how many previous versions of each file are kept
+	BackupCount = 3
+)
+
+// backupPath returns the path of the nth backup of path (0 is the newest)
+func backupPath(path string, n int) string {
+	if n == 0 {
+		return path + BackupExt
+	}
+	return fmt.Sprintf("%s%s.%d", path, BackupExt, n)
+}
+
+// rotateBackups shifts existing backups of path one slot older and copies
+// the current file into the newest slot. A missing file is not an error.
+func rotateBackups(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	for n := BackupCount - 1; n > 0; n-- {
+		older := backupPath(path, n-1)
+		if _, err := os.Stat(older); err == nil {
+			if err := os.Rename(older, backupPath(path, n)); err != nil {
+				return fmt.Errorf("failed to rotate backup %s: %w", older, err)
+			}
+		}
+	}
+
+	newest := backupPath(path, 0)
+	os.Remove(newest)
+
+	// A hard link is free; fall back to copying where links are unsupported
+	if err := os.Link(path, newest); err != nil {
+		if err := copyFile(path, newest); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// moveWithBackups renames src and its backups to dst. It fails rather than
+// replace an existing dst.
+func moveWithBackups(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("cannot move %s: %s already exists", src, dst)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", dst, err)
+	}
+
+	if err := os.Rename(src, dst); err != nil {
+		return fmt.Errorf("failed to move %s to %s: %w", src, dst, err)
+	}
+	for n := 0; n < BackupCount; n++ {
+		if err := os.Rename(backupPath(src, n), backupPath(dst, n)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to move backup of %s: %w", src, err)
+		}
+	}
+
+	atomicfile.SyncDir(filepath.Dir(src))
+	atomicfile.SyncDir(filepath.Dir(dst))
+	return nil
+}
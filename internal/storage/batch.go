@@ -0,0 +1,193 @@
+package storage
+
+import (
+	"sort"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// Batch runs fn against a repository that keeps the todo files it loads in
+// memory, then saves every file fn changed once. Acting on many todos of the
+// same file thus loads and saves it a single time. fn must only use tx. The
+// changes become one undoable action; when fn fails, none of them are saved.
+func (r *Repository) Batch(fn func(tx *Repository) error) error {
+	return r.withLock(func() error {
+		store := newBatchStore(r.storage)
+		tx := &Repository{storage: store, session: r.session, recording: r.recording, locked: true}
+
+		if err := fn(tx); err != nil {
+			// Nothing was saved, so there is nothing to undo either
+			if r.recording != nil {
+				r.recording.Changes = nil
+			}
+			return err
+		}
+		return store.flush()
+	})
+}
+
+// batchStore is a Store that holds the todo files of a batch in memory until
+// they are flushed. Everything else goes straight to the store underneath.
+type batchStore struct {
+	Store
+
+	files map[string]*batchFile
+	order []string // keys of files, in the order they were first loaded
+}
+
+// batchFile is a day file or named bucket held by a batchStore
+type batchFile struct {
+	date    *string // nil for a named bucket
+	name    string
+	todos   []models.Todo
+	changed bool
+	deleted bool
+}
+
+// newBatchStore creates a batch on top of store
+func newBatchStore(store Store) *batchStore {
+	return &batchStore{Store: store, files: make(map[string]*batchFile)}
+}
+
+// file returns the file for date, or for the named bucket name when date is
+// nil, loading it on first use. The nil date of LoadTodos is the default
+// list, so it shares the file of that bucket.
+func (s *batchStore) file(date *string, name string) (*batchFile, error) {
+	key := "named:" + name
+	if date != nil {
+		key = "date:" + *date
+	}
+	if f, ok := s.files[key]; ok {
+		return f, nil
+	}
+
+	f := &batchFile{date: date, name: name}
+	var err error
+	if date != nil {
+		f.todos, err = s.Store.LoadTodos(date)
+	} else {
+		f.todos, err = s.Store.LoadNamed(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.files[key] = f
+	s.order = append(s.order, key)
+	return f, nil
+}
+
+// load returns a copy of the todos in a file
+func (s *batchStore) load(date *string, name string) ([]models.Todo, error) {
+	f, err := s.file(date, name)
+	if err != nil {
+		return nil, err
+	}
+	return copyTodos(f.todos), nil
+}
+
+// save replaces the todos in a file, or marks it deleted
+func (s *batchStore) save(date *string, name string, todos []models.Todo, deleted bool) error {
+	f, err := s.file(date, name)
+	if err != nil {
+		return err
+	}
+	f.todos = copyTodos(todos)
+	f.changed = true
+	f.deleted = deleted
+	return nil
+}
+
+// LoadTodos returns the todos of a day file, or of the default list
+func (s *batchStore) LoadTodos(date *string) ([]models.Todo, error) {
+	if date == nil {
+		return s.load(nil, DefaultListBucket)
+	}
+	return s.load(date, "")
+}
+
+// SaveTodos replaces the todos of a day file, or of the default list
+func (s *batchStore) SaveTodos(todos []models.Todo, date *string) error {
+	if date == nil {
+		return s.save(nil, DefaultListBucket, todos, false)
+	}
+	return s.save(date, "", todos, false)
+}
+
+// DeleteTodos removes a day file, or the default list
+func (s *batchStore) DeleteTodos(date *string) error {
+	if date == nil {
+		return s.save(nil, DefaultListBucket, nil, true)
+	}
+	return s.save(date, "", nil, true)
+}
+
+// ListDates returns the dates of the day files, including those created in
+// the batch and without those it deleted
+func (s *batchStore) ListDates() ([]string, error) {
+	stored, err := s.Store.ListDates()
+	if err != nil {
+		return nil, err
+	}
+
+	exists := make(map[string]bool)
+	for _, date := range stored {
+		exists[date] = true
+	}
+	for _, f := range s.files {
+		if f.date != nil && f.changed {
+			exists[*f.date] = !f.deleted
+		}
+	}
+
+	dates := make([]string, 0, len(exists))
+	for date, ok := range exists {
+		if ok {
+			dates = append(dates, date)
+		}
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// LoadNamed returns the todos of a named bucket
+func (s *batchStore) LoadNamed(name string) ([]models.Todo, error) {
+	return s.load(nil, name)
+}
+
+// SaveNamed replaces the todos of a named bucket
+func (s *batchStore) SaveNamed(name string, todos []models.Todo) error {
+	return s.save(nil, name, todos, false)
+}
+
+// DeleteNamed removes a named bucket
+func (s *batchStore) DeleteNamed(name string) error {
+	return s.save(nil, name, nil, true)
+}
+
+// flush saves every changed file to the store underneath, once
+func (s *batchStore) flush() error {
+	for _, key := range s.order {
+		f := s.files[key]
+		if !f.changed {
+			continue
+		}
+
+		var err error
+		switch {
+		case f.date != nil && f.deleted:
+			err = s.Store.DeleteTodos(f.date)
+		case f.date != nil:
+			err = s.Store.SaveTodos(f.todos, f.date)
+		case f.deleted:
+			err = s.Store.DeleteNamed(f.name)
+		default:
+			err = s.Store.SaveNamed(f.name, f.todos)
+		}
+		if err != nil {
+			return err
+		}
+		f.changed = false
+	}
+	return nil
+}
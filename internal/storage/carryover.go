@@ -0,0 +1,135 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// OverdueTodos returns the unfinished todos dated before today, oldest
+// first. Past occurrences of recurring todos are not included: a missed
+// repeat is not carried along.
+func (r *Repository) OverdueTodos(today string) ([]models.Todo, error) {
+	dates, err := r.GetDates()
+	if err != nil {
+		return nil, err
+	}
+
+	var overdue []models.Todo
+	for _, date := range dates {
+		if date >= today {
+			break
+		}
+
+		todos, err := r.storage.LoadTodos(&date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load todos for %s: %w", date, err)
+		}
+		for _, todo := range todos {
+			if !todo.Completed {
+				overdue = append(overdue, todo)
+			}
+		}
+	}
+
+	models.SortTodos(overdue)
+	return overdue, nil
+}
+
+// CarryOver moves every unfinished todo dated before today to today and
+// records it in the carry-over log. It returns the todos it moved.
+func (r *Repository) CarryOver(today string) ([]models.Todo, error) {
+	var carried []models.Todo
+	err := r.withLock(func() error {
+		overdue, err := r.OverdueTodos(today)
+		if err != nil {
+			return err
+		}
+
+		log, err := r.storage.LoadCarryOverLog()
+		if err != nil {
+			return fmt.Errorf("failed to load carry-over log: %w", err)
+		}
+
+		now := time.Now()
+		for _, todo := range overdue {
+			moved, err := r.carryOverTodo(&log, todo, today, now)
+			if err != nil {
+				return err
+			}
+			carried = append(carried, moved)
+		}
+
+		log.CheckedOn = today
+		return r.storage.SaveCarryOverLog(log)
+	})
+	return carried, err
+}
+
+// CarryOverTodo moves a single todo from a past day to today and records it
+// in the carry-over log. It returns the moved todo.
+func (r *Repository) CarryOverTodo(todo models.Todo, today string) (models.Todo, error) {
+	var moved models.Todo
+	err := r.withLock(func() error {
+		if todo.Date == nil || *todo.Date >= today || todo.IsRecurring() {
+			return fmt.Errorf("only one-off todos from past days can be carried over")
+		}
+
+		log, err := r.storage.LoadCarryOverLog()
+		if err != nil {
+			return fmt.Errorf("failed to load carry-over log: %w", err)
+		}
+
+		moved, err = r.carryOverTodo(&log, todo, today, time.Now())
+		if err != nil {
+			return err
+		}
+		return r.storage.SaveCarryOverLog(log)
+	})
+	return moved, err
+}
+
+// carryOverTodo moves todo to today and adds it to log; the caller must hold
+// the lock and save log
+func (r *Repository) carryOverTodo(log *models.CarryOverLog, todo models.Todo, today string, now time.Time) (models.Todo, error) {
+	from := *todo.Date
+	if todo.CarriedFrom == "" {
+		todo.CarriedFrom = from
+	}
+	if err := r.moveTodo(todo, bucket{date: &today}); err != nil {
+		return todo, err
+	}
+
+	todo.Date = &today
+	log.Record(models.CarriedTodo{ID: todo.ID, Title: todo.Title, From: from, To: today, At: now})
+	return todo, nil
+}
+
+// CarryOverDue reports whether the carry-over policy has not run on today yet
+func (r *Repository) CarryOverDue(today string) (bool, error) {
+	log, err := r.storage.LoadCarryOverLog()
+	if err != nil {
+		return false, fmt.Errorf("failed to load carry-over log: %w", err)
+	}
+	return log.CheckedOn != today, nil
+}
+
+// MarkCarryOverChecked records that the carry-over policy ran on today
+// without moving anything, so it is not asked again until the next day
+func (r *Repository) MarkCarryOverChecked(today string) error {
+	return r.withLock(func() error {
+		log, err := r.storage.LoadCarryOverLog()
+		if err != nil {
+			return fmt.Errorf("failed to load carry-over log: %w", err)
+		}
+
+		log.CheckedOn = today
+		return r.storage.SaveCarryOverLog(log)
+	})
+}
+
+// GetCarryOverLog returns the record of todos carried over from past days
+func (r *Repository) GetCarryOverLog() (models.CarryOverLog, error) {
+	return r.storage.LoadCarryOverLog()
+}
@@ -0,0 +1,240 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+const (
+	// HomeEnvVar overrides the data directory when no flag is given
+	HomeEnvVar = "TEDO_HOME"
+
+	// migratedMarker is left in the legacy ./data directory once its files
+	// have been copied to the resolved data directory
+	migratedMarker = ".tedo-migrated"
+)
+
+// ResolveDataDir returns the directory todos are stored in. The explicit
+// value (from --data-dir) wins, then $TEDO_HOME, then $XDG_DATA_HOME/tedo,
+// falling back to ~/.local/share/tedo.
+func ResolveDataDir(explicit string) (string, error) {
+	if explicit != "" {
+		return filepath.Abs(expandHome(explicit))
+	}
+
+	if home := os.Getenv(HomeEnvVar); home != "" {
+		return filepath.Abs(expandHome(home))
+	}
+
+	if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" && filepath.IsAbs(xdg) {
+		return filepath.Join(xdg, "tedo"), nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine home directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "tedo"), nil
+}
+
+// expandHome replaces a leading ~ with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// MigrateLegacyDataDir copies todo files from the legacy ./data directory
+// into dataDir. Only files named like the old layout, YYYY-MM-DD.json and
+// general.json, are copied; a ./data directory without any belongs to
+// something else and is left alone. Files that already exist in dataDir are
+// left untouched. It runs once: afterwards a marker file is written to ./data
+// and later calls are no-ops. It returns the number of files copied.
+func MigrateLegacyDataDir(dataDir string) (int, error) {
+	legacyDir, err := filepath.Abs(DataDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve legacy data directory: %w", err)
+	}
+	return migrateLegacyDir(legacyDir, dataDir)
+}
+
+// migrateLegacyDir is MigrateLegacyDataDir for the legacy directory legacyDir
+func migrateLegacyDir(legacyDir, dataDir string) (int, error) {
+	target, err := filepath.Abs(dataDir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resolve data directory: %w", err)
+	}
+
+	if legacyDir == target {
+		return 0, nil
+	}
+
+	if _, err := os.Stat(filepath.Join(legacyDir, migratedMarker)); err == nil {
+		return 0, nil
+	}
+
+	entries, err := os.ReadDir(legacyDir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read legacy data directory %s: %w", legacyDir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isLegacyTodoFile(entry.Name()) {
+			files = append(files, entry.Name())
+		}
+	}
+
+	if len(files) == 0 {
+		return 0, nil
+	}
+
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		return 0, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	copied := 0
+	for _, name := range files {
+		dst := filepath.Join(target, name)
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+
+		if err := copyFile(filepath.Join(legacyDir, name), dst); err != nil {
+			return copied, err
+		}
+		copied++
+	}
+
+	note := fmt.Sprintf("Todos in this directory were migrated to %s\n", target)
+	if err := os.WriteFile(filepath.Join(legacyDir, migratedMarker), []byte(note), 0o644); err != nil {
+		return copied, fmt.Errorf("failed to write migration marker: %w", err)
+	}
+
+	return copied, nil
+}
+
+// isLegacyTodoFile reports whether name is a todo file of the legacy ./data
+// layout: a day file or the general file
+func isLegacyTodoFile(name string) bool {
+	if name == GeneralFile {
+		return true
+	}
+	date, ok := strings.CutSuffix(name, DatedFileExt)
+	if !ok {
+		return false
+	}
+	_, err := models.ParseDate(date)
+	return err == nil
+}
+
+// MigrateGeneralFile moves the legacy general todo file into the lists
+// directory, where it becomes the default list. If the default list already
+// exists, e.g. because ./data was migrated after lists were created, the
+// legacy todos missing from it are merged in and the file is kept under
+// BackupDir instead. It reports whether the file was moved or merged.
+func MigrateGeneralFile(dataDir string) (bool, error) {
+	src := filepath.Join(dataDir, GeneralFile)
+	if _, err := os.Stat(src); os.IsNotExist(err) {
+		return false, nil
+	}
+
+	store := NewJSONStorageAt(dataDir)
+	dst := store.getFilePath(nil)
+	if _, err := os.Stat(dst); os.IsNotExist(err) {
+		if err := moveWithBackups(src, dst); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	unlock, err := acquireLock(store)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	if err := os.MkdirAll(filepath.Join(dataDir, BackupDir), 0o755); err != nil {
+		return false, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	backupDir, err := os.MkdirTemp(filepath.Join(dataDir, BackupDir), "merge-"+time.Now().Format("20060102-150405")+"-")
+	if err != nil {
+		return false, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	if err := copyFile(src, filepath.Join(backupDir, GeneralFile)); err != nil {
+		return false, err
+	}
+
+	if err := store.mergeGeneralFile(src); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// mergeGeneralFile adds the todos of the legacy general file at src that are
+// missing from the default list to it, then removes src and its backups
+func (s *JSONStorage) mergeGeneralFile(src string) error {
+	legacy, err := s.readTodos(src)
+	if err != nil {
+		return err
+	}
+	todos, err := s.LoadNamed(DefaultListBucket)
+	if err != nil {
+		return err
+	}
+
+	for _, todo := range legacy {
+		if findTodo(todos, todo.ID) < 0 {
+			todos = append(todos, todo)
+		}
+	}
+	if err := s.SaveNamed(DefaultListBucket, todos); err != nil {
+		return err
+	}
+
+	if err := removeFile(src); err != nil {
+		return err
+	}
+	for n := 0; n < BackupCount; n++ {
+		if err := removeFile(backupPath(src, n)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyFile copies the contents of src to a new file dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", src, err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", dst, err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy %s: %w", src, err)
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// writeFiles creates each named file in dir with contents
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// fileNames returns the names in dir, sorted, or nil if it does not exist
+func fileNames(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestMigrateLegacyDirCopiesTodoFiles(t *testing.T) {
+	legacy, target := t.TempDir(), filepath.Join(t.TempDir(), "tedo")
+	writeFiles(t, legacy, map[string]string{
+		"2025-06-15.json": `{"version":2,"todos":[]}`,
+		"2025-06-16.json": `{"version":2,"todos":[]}`,
+		GeneralFile:       `{"version":2,"todos":[]}`,
+		"package.json":    `{"name":"fixture"}`,
+		"2025-13-45.json": `{}`,
+		"notes.txt":       "not a todo file",
+	})
+	if err := os.Mkdir(filepath.Join(legacy, "2025-06-17.json"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, target, map[string]string{"2025-06-16.json": "kept"})
+
+	copied, err := migrateLegacyDir(legacy, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied != 2 {
+		t.Errorf("copied %d files, want 2", copied)
+	}
+
+	want := []string{"2025-06-15.json", "2025-06-16.json", GeneralFile}
+	if got := fileNames(t, target); !equalStrings(got, want) {
+		t.Errorf("data directory holds %q, want %q", got, want)
+	}
+	if kept, err := os.ReadFile(filepath.Join(target, "2025-06-16.json")); err != nil || string(kept) != "kept" {
+		t.Errorf("an existing file was overwritten: %q, %v", kept, err)
+	}
+	if _, err := os.Stat(filepath.Join(legacy, migratedMarker)); err != nil {
+		t.Errorf("no migration marker was written: %v", err)
+	}
+
+	// Later runs do nothing
+	if err := os.Remove(filepath.Join(target, "2025-06-15.json")); err != nil {
+		t.Fatal(err)
+	}
+	if copied, err := migrateLegacyDir(legacy, target); err != nil || copied != 0 {
+		t.Errorf("second migration copied %d files, %v", copied, err)
+	}
+}
+
+func TestMigrateLegacyDirSkipsOtherData(t *testing.T) {
+	legacy, target := t.TempDir(), filepath.Join(t.TempDir(), "tedo")
+	files := map[string]string{
+		"package.json":  `{"name":"fixture"}`,
+		"fixtures.json": `[1, 2, 3]`,
+		"readme.md":     "# Fixtures",
+	}
+	writeFiles(t, legacy, files)
+
+	copied, err := migrateLegacyDir(legacy, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if copied != 0 {
+		t.Errorf("copied %d files, want none", copied)
+	}
+	if got := fileNames(t, target); got != nil {
+		t.Errorf("data directory holds %q, want it not created", got)
+	}
+	if got := fileNames(t, legacy); len(got) != len(files) {
+		t.Errorf("the directory was changed to %q", got)
+	}
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+var (
+	// ErrDependencyCycle is returned when a todo would end up waiting on itself
+	ErrDependencyCycle = errors.New("dependency cycle")
+
+	// ErrBlockedBySeries is returned when a whole recurring series is given as
+	// a blocker instead of one of its occurrences
+	ErrBlockedBySeries = errors.New("a todo can only be blocked by a single occurrence of a recurring todo")
+)
+
+// Dependencies looks up blockers and dependents across every date, list and
+// recurring series
+type Dependencies struct {
+	todos map[string]models.Todo // stored todos and series by ID
+}
+
+// LoadDependencies loads every todo so blockers can be looked up by ID
+func (r *Repository) LoadDependencies() (Dependencies, error) {
+	all, err := r.GetAllTodos()
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	deps := Dependencies{todos: make(map[string]models.Todo, len(all))}
+	for _, todo := range all {
+		deps.todos[todo.ID] = todo
+	}
+	return deps, nil
+}
+
+// Find returns the todo with the given ID, expanding occurrence IDs
+func (d Dependencies) Find(id string) (models.Todo, bool) {
+	if seriesID, date, ok := models.SplitOccurrenceID(id); ok {
+		series, found := d.todos[seriesID]
+		if !found || !series.OccursOn(date) {
+			return models.Todo{}, false
+		}
+		return series.Occurrence(date), true
+	}
+
+	todo, found := d.todos[id]
+	return todo, found
+}
+
+// OpenBlockers returns the blockers of todo that are not done yet. Blockers
+// that no longer exist do not block.
+func (d Dependencies) OpenBlockers(todo models.Todo) []models.Todo {
+	var open []models.Todo
+	for _, id := range todo.BlockedBy {
+		if blocker, ok := d.Find(id); ok && !blocker.Completed {
+			open = append(open, blocker)
+		}
+	}
+	return open
+}
+
+// IsBlocked reports whether todo is waiting on an open blocker
+func (d Dependencies) IsBlocked(todo models.Todo) bool {
+	return len(d.OpenBlockers(todo)) > 0
+}
+
+// Unblocked returns the open todos that declare id as a blocker and have no
+// other open blocker left
+func (d Dependencies) Unblocked(id string) []models.Todo {
+	var unblocked []models.Todo
+	for _, todo := range d.todos {
+		if todo.IsBlockedBy(id) && !todo.Completed && !d.IsBlocked(todo) {
+			unblocked = append(unblocked, todo)
+		}
+	}
+	models.SortTodos(unblocked)
+	return unblocked
+}
+
+// ResolveBlockers looks up the todos that refs refer to, by ID or unique
+// prefix, and returns their IDs. It fails if todo may not be blocked by
+// them, for example because that would create a cycle.
+func (r *Repository) ResolveBlockers(todo models.Todo, refs []string) ([]string, error) {
+	var ids []string
+	for _, ref := range refs {
+		if strings.TrimSpace(ref) == "" {
+			continue
+		}
+
+		blocker, err := r.FindTodo(ref)
+		if err != nil {
+			return nil, err
+		}
+		if blocker.IsRecurring() && !blocker.IsOccurrence() {
+			return nil, fmt.Errorf("%w: %s", ErrBlockedBySeries, blocker.ID)
+		}
+		if dependencyNode(blocker.ID) == dependencyNode(todo.ID) {
+			return nil, fmt.Errorf("%w: %s cannot block itself", ErrDependencyCycle, todo.Title)
+		}
+		if !containsID(ids, blocker.ID) {
+			ids = append(ids, blocker.ID)
+		}
+	}
+
+	deps, err := r.LoadDependencies()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if path := deps.pathTo(id, dependencyNode(todo.ID), nil); path != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDependencyCycle, deps.describePath(append([]string{todo.ID}, path...)))
+		}
+	}
+	return ids, nil
+}
+
+// SetBlockedBy replaces the blockers of todo with the todos refs refer to and
+// returns the saved todo. An empty refs removes every blocker.
+func (r *Repository) SetBlockedBy(todo models.Todo, refs []string) (models.Todo, error) {
+	err := r.withLock(func() error {
+		ids, err := r.ResolveBlockers(todo, refs)
+		if err != nil {
+			return err
+		}
+
+		todo.BlockedBy = ids
+		return r.updateTodo(&todo)
+	})
+	return todo, err
+}
+
+// pathTo follows blockers from id and returns the IDs leading to target, or
+// nil if target cannot be reached. seen guards against existing cycles.
+func (d Dependencies) pathTo(id, target string, seen map[string]bool) []string {
+	node := dependencyNode(id)
+	if node == target {
+		return []string{id}
+	}
+	if seen == nil {
+		seen = make(map[string]bool)
+	}
+	if seen[node] {
+		return nil
+	}
+	seen[node] = true
+
+	for _, next := range d.todos[node].BlockedBy {
+		if path := d.pathTo(next, target, seen); path != nil {
+			return append([]string{id}, path...)
+		}
+	}
+	return nil
+}
+
+// describePath names the todos of a dependency path, e.g. "a → b → a"
+func (d Dependencies) describePath(ids []string) string {
+	names := make([]string, len(ids))
+	for i, id := range ids {
+		names[i] = id
+		if todo, ok := d.Find(id); ok {
+			names[i] = todo.Title
+		}
+	}
+	return strings.Join(names, " → ")
+}
+
+// dependencyNode returns the ID whose blockers apply to id. Occurrences
+// share the blockers of their series.
+func dependencyNode(id string) string {
+	if seriesID, _, ok := models.SplitOccurrenceID(id); ok {
+		return seriesID
+	}
+	return id
+}
+
+// containsID reports whether ids contains id
+func containsID(ids []string, id string) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,302 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+var (
+	// ErrNothingToUndo is returned by Undo when the history is empty
+	ErrNothingToUndo = errors.New("nothing to undo")
+
+	// ErrNothingToRedo is returned by Redo when no undone action is left
+	ErrNothingToRedo = errors.New("nothing to redo")
+
+	// ErrHistoryConflict is returned when the todos an action changed were
+	// changed again outside of the history, e.g. by another tedo process
+	ErrHistoryConflict = errors.New("todos were changed elsewhere since")
+)
+
+// RecordHistory makes every later change to todos an action that can be
+// undone, stamped with session. Changes made before, or by repositories
+// that do not record, are left out of the history.
+func (r *Repository) RecordHistory(session string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.session = session
+	r.sessionStart = time.Now()
+}
+
+// Session returns the session changes are recorded for, empty when history
+// is not recorded
+func (r *Repository) Session() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.session
+}
+
+// record runs fn, then adds the todo changes it made to the history as one
+// action. Changes that were saved before fn failed are recorded too, so
+// they can still be undone. The caller must hold the lock.
+func (r *Repository) record(fn func() error) error {
+	r.recording = &models.HistoryEntry{Session: r.session, At: time.Now()}
+	err := fn()
+	entry := *r.recording
+	r.recording = nil
+
+	if len(entry.Changes) == 0 {
+		return err
+	}
+
+	history, loadErr := r.storage.LoadHistory()
+	if loadErr == nil {
+		history.Record(entry)
+		loadErr = r.storage.SaveHistory(history)
+	}
+	if err == nil && loadErr != nil {
+		return fmt.Errorf("failed to save history: %w", loadErr)
+	}
+	return err
+}
+
+// History returns the actions that can be undone and redone
+func (r *Repository) History() (models.History, error) {
+	return r.storage.LoadHistory()
+}
+
+// Undo reverts the most recent action of this session and returns it. Once
+// the session has none left, actions made before it started, e.g. before
+// tedo was restarted, can be undone too. Actions another running session
+// made since are left to it.
+func (r *Repository) Undo() (models.HistoryEntry, error) {
+	return r.replay(true)
+}
+
+// Redo makes the action this session undid most recently again and returns it
+func (r *Repository) Redo() (models.HistoryEntry, error) {
+	return r.replay(false)
+}
+
+// replay moves the latest action this session may replay from the undo
+// stack to the redo stack, restoring the todos as they were before it, or
+// the other way round. The session that undid an action owns it from then on.
+func (r *Repository) replay(undo bool) (models.HistoryEntry, error) {
+	var entry models.HistoryEntry
+	err := r.lockData(func() error {
+		history, err := r.storage.LoadHistory()
+		if err != nil {
+			return fmt.Errorf("failed to load history: %w", err)
+		}
+
+		from, to, empty := &history.Undo, &history.Redo, ErrNothingToUndo
+		if !undo {
+			from, to, empty = &history.Redo, &history.Undo, ErrNothingToRedo
+		}
+		i := r.replayable(*from, undo)
+		if i < 0 {
+			return empty
+		}
+		entry = (*from)[i]
+
+		if err := r.applyChanges(entry.Changes, undo); err != nil {
+			return err
+		}
+
+		moved := entry
+		moved.Session = r.session
+		*from = append((*from)[:i:i], (*from)[i+1:]...)
+		*to = append(*to, moved)
+		return r.storage.SaveHistory(history)
+	})
+	return entry, err
+}
+
+// replayable returns the index of the newest action in entries that this
+// session may undo or redo, see Undo and Redo, or -1 if there is none. The
+// caller must hold the lock.
+func (r *Repository) replayable(entries []models.HistoryEntry, undo bool) int {
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Session == r.session {
+			return i
+		}
+	}
+	for i := len(entries) - 1; i >= 0 && undo; i-- {
+		if entries[i].At.Before(r.sessionStart) {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyChanges puts every changed todo back the way it was before the
+// changes, or as they left it when undo is false. Nothing is written unless
+// every todo is still as the other side of the change left it. The caller
+// must hold the lock.
+func (r *Repository) applyChanges(changes []models.TodoChange, undo bool) error {
+	buckets := make(map[string][]models.Todo)
+	for _, change := range changes {
+		key := changeKey(change)
+		todos, ok := buckets[key]
+		if !ok {
+			var err error
+			if todos, err = r.readBucket(change.Date, change.Bucket); err != nil {
+				return err
+			}
+		}
+
+		current, want := change.After, change.Before
+		if !undo {
+			current, want = change.Before, change.After
+		}
+
+		i := findTodo(todos, change.ID())
+		if i < 0 && current != nil || i >= 0 && (current == nil || !sameTodo(todos[i], *current)) {
+			return fmt.Errorf("%w: %s", ErrHistoryConflict, change.ID())
+		}
+
+		switch {
+		case want == nil:
+			todos = append(todos[:i:i], todos[i+1:]...)
+		case i < 0:
+			todos = append(todos, *want)
+		default:
+			todos[i] = *want
+		}
+		buckets[key] = todos
+	}
+
+	for _, change := range changes {
+		key := changeKey(change)
+		todos, ok := buckets[key]
+		if !ok {
+			continue
+		}
+		if err := r.writeBucket(change.Date, change.Bucket, todos); err != nil {
+			return err
+		}
+		delete(buckets, key)
+	}
+	return nil
+}
+
+// changeKey identifies the file a change was made in
+func changeKey(change models.TodoChange) string {
+	if change.Date != nil {
+		return *change.Date
+	}
+	return change.Bucket
+}
+
+// readBucket returns the todos of the day file for date, or of the named
+// bucket name when date is nil
+func (r *Repository) readBucket(date *string, name string) ([]models.Todo, error) {
+	if date != nil {
+		return r.storage.LoadTodos(date)
+	}
+	return r.storage.LoadNamed(name)
+}
+
+// writeBucket replaces the todos of the day file for date, or of the named
+// bucket name when date is nil. Day files left empty are removed. While an
+// action is recorded, the todos it changes are added to it. The caller must
+// hold the lock.
+func (r *Repository) writeBucket(date *string, name string, todos []models.Todo) error {
+	if r.recording != nil {
+		before, err := r.readBucket(date, name)
+		if err != nil {
+			return fmt.Errorf("failed to load todos for history: %w", err)
+		}
+		r.recordChanges(date, name, before, todos)
+	}
+
+	switch {
+	case date == nil:
+		return r.storage.SaveNamed(name, todos)
+	case len(todos) == 0:
+		return r.storage.DeleteTodos(date)
+	default:
+		return r.storage.SaveTodos(todos, date)
+	}
+}
+
+// recordChanges adds the todos that differ between before and after to the
+// recorded action. A todo changed twice by one action keeps a single
+// change from its first state to its last.
+func (r *Repository) recordChanges(date *string, name string, before, after []models.Todo) {
+	var changes []models.TodoChange
+	for i := range before {
+		j := findTodo(after, before[i].ID)
+		if j < 0 {
+			changes = append(changes, models.TodoChange{Before: &before[i]})
+		} else if !sameTodo(before[i], after[j]) {
+			changes = append(changes, models.TodoChange{Before: &before[i], After: &after[j]})
+		}
+	}
+	for i := range after {
+		if findTodo(before, after[i].ID) < 0 {
+			changes = append(changes, models.TodoChange{After: &after[i]})
+		}
+	}
+
+	entry := r.recording
+	for _, change := range changes {
+		change.Date, change.Bucket = date, name
+		if change.Before != nil {
+			before := change.Before.Clone()
+			change.Before = &before
+		}
+		if change.After != nil {
+			after := change.After.Clone()
+			change.After = &after
+		}
+
+		k := findChange(entry.Changes, change)
+		switch {
+		case k < 0:
+			entry.Changes = append(entry.Changes, change)
+		case entry.Changes[k].Before == nil && change.After == nil,
+			entry.Changes[k].Before != nil && change.After != nil && sameTodo(*entry.Changes[k].Before, *change.After):
+			// Back to where the action started
+			entry.Changes = append(entry.Changes[:k], entry.Changes[k+1:]...)
+		default:
+			entry.Changes[k].After = change.After
+		}
+	}
+}
+
+// findChange returns the index of the change to the same todo in the same
+// file as change, or -1
+func findChange(changes []models.TodoChange, change models.TodoChange) int {
+	for i := range changes {
+		if changes[i].ID() == change.ID() && changeKey(changes[i]) == changeKey(change) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findTodo returns the index of the todo with the given ID, or -1
+func findTodo(todos []models.Todo, id string) int {
+	for i := range todos {
+		if todos[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// sameTodo reports whether two todos would be saved the same. Comparing the
+// encoded todos ignores what the files do not keep, such as the monotonic
+// clock readings of timestamps.
+func sameTodo(a, b models.Todo) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
@@ -0,0 +1,464 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// newHistoryRepos returns a repository that records history and one that
+// changes the same todos without recording, like another tedo process
+func newHistoryRepos() (recorded, outside *Repository) {
+	store := NewMemoryStore()
+	recorded = NewRepository(WithStore(store))
+	recorded.RecordHistory("session")
+	return recorded, NewRepository(WithStore(store))
+}
+
+// datedTodo creates a todo on date
+func datedTodo(id, title, date string) models.Todo {
+	todo := models.NewTodo(title, "", &date)
+	todo.ID = id
+	return todo
+}
+
+// addTodo adds todo to repo and returns it as stored
+func addTodo(t *testing.T, repo *Repository, todo models.Todo) models.Todo {
+	t.Helper()
+	if err := repo.AddTodo(todo); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := repo.FindTodo(todo.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored
+}
+
+// titles returns the titles of the todos on date, in order
+func titles(t *testing.T, repo *Repository, date string) []string {
+	t.Helper()
+	todos, err := repo.GetTodosForDate(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var titles []string
+	for _, todo := range todos {
+		titles = append(titles, todo.Title)
+	}
+	return titles
+}
+
+// equalStrings reports whether a and b hold the same strings in order
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestRecordChanges(t *testing.T) {
+	date := "2025-06-15"
+	a := datedTodo("a", "Write report", date)
+	b := datedTodo("b", "Buy milk", date)
+	renamed := a
+	renamed.Title = "Write the report"
+	renamedAgain := a
+	renamedAgain.Title = "Send the report"
+
+	tests := []struct {
+		name  string
+		steps [][]models.Todo // the file after each write, starting from the first
+		want  []string        // "before -> after" per change, "-" for no todo
+	}{
+		{"add", [][]models.Todo{{a}, {a, b}}, []string{"- -> Buy milk"}},
+		{"delete", [][]models.Todo{{a, b}, {a}}, []string{"Buy milk -> -"}},
+		{"update", [][]models.Todo{{a}, {renamed}}, []string{"Write report -> Write the report"}},
+		{"unchanged", [][]models.Todo{{a}, {a}}, nil},
+		{"updated twice", [][]models.Todo{{a}, {renamed}, {renamedAgain}}, []string{"Write report -> Send the report"}},
+		{"added then updated", [][]models.Todo{{}, {a}, {renamed}}, []string{"- -> Write the report"}},
+		{"updated then deleted", [][]models.Todo{{a}, {renamed}, {}}, []string{"Write report -> -"}},
+		{"added then deleted", [][]models.Todo{{}, {a}, {}}, nil},
+		{"updated and back", [][]models.Todo{{a}, {renamed}, {a}}, nil},
+		{"deleted and added back", [][]models.Todo{{a, b}, {b}, {a, b}}, nil},
+		{"two todos", [][]models.Todo{{a, b}, {renamed}}, []string{"Write report -> Write the report", "Buy milk -> -"}},
+	}
+
+	for _, tt := range tests {
+		store := NewMemoryStore()
+		if err := store.SaveTodos(tt.steps[0], &date); err != nil {
+			t.Fatal(err)
+		}
+		r := NewRepository(WithStore(store))
+		r.recording = &models.HistoryEntry{}
+		for _, todos := range tt.steps[1:] {
+			if err := r.writeBucket(&date, "", todos); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		var got []string
+		for _, change := range r.recording.Changes {
+			if change.Date == nil || *change.Date != date {
+				t.Errorf("%s: change of %s recorded for the wrong file", tt.name, change.ID())
+			}
+			got = append(got, describeChange(change))
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("%s: recorded %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// describeChange writes a change as "before -> after" by title
+func describeChange(change models.TodoChange) string {
+	before, after := "-", "-"
+	if change.Before != nil {
+		before = change.Before.Title
+	}
+	if change.After != nil {
+		after = change.After.Title
+	}
+	return before + " -> " + after
+}
+
+func TestRecordChangesKeepsFilesApart(t *testing.T) {
+	from, to := "2025-06-15", "2025-06-16"
+	repo, _ := newHistoryRepos()
+	todo := addTodo(t, repo, datedTodo("a", "Write report", from))
+	if err := repo.MoveTodo(todo, &to); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := repo.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Undo) != 2 {
+		t.Fatalf("recorded %d actions, want 2", len(history.Undo))
+	}
+	move := history.Undo[1]
+	if len(move.Changes) != 2 {
+		t.Fatalf("the move recorded %d changes, want one per file", len(move.Changes))
+	}
+	for _, change := range move.Changes {
+		switch {
+		case change.Before != nil && change.After == nil && *change.Date == from:
+		case change.Before == nil && change.After != nil && *change.Date == to:
+		default:
+			t.Errorf("unexpected change on %s: %s", *change.Date, describeChange(change))
+		}
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	date := "2025-06-15"
+	repo, _ := newHistoryRepos()
+	todo := addTodo(t, repo, datedTodo("a", "Write report", date))
+	todo.Title = "Send the report"
+	if err := repo.UpdateTodo(&todo); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		undo bool
+		want []string
+	}{
+		{true, []string{"Write report"}},
+		{true, nil},
+		{false, []string{"Write report"}},
+		{false, []string{"Send the report"}},
+		{true, []string{"Write report"}},
+	}
+	for i, step := range steps {
+		replay := repo.Redo
+		if step.undo {
+			replay = repo.Undo
+		}
+		entry, err := replay()
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		if entry.Session != "session" {
+			t.Errorf("step %d: replayed an action of session %q", i, entry.Session)
+		}
+		if got := titles(t, repo, date); !equalStrings(got, step.want) {
+			t.Errorf("step %d: todos are %q, want %q", i, got, step.want)
+		}
+	}
+
+	// A new action drops what was undone
+	other := datedTodo("b", "Buy milk", date)
+	if err := repo.AddTodo(other); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Redo after a new action: %v, want %v", err, ErrNothingToRedo)
+	}
+
+	for {
+		if _, err := repo.Undo(); err != nil {
+			if !errors.Is(err, ErrNothingToUndo) {
+				t.Fatalf("Undo: %v", err)
+			}
+			break
+		}
+	}
+	if got := titles(t, repo, date); len(got) != 0 {
+		t.Errorf("todos after undoing everything are %q, want none", got)
+	}
+}
+
+func TestBatchRecordsOneAction(t *testing.T) {
+	date := "2025-06-15"
+	repo, _ := newHistoryRepos()
+	a := addTodo(t, repo, datedTodo("a", "Write report", date))
+	b := addTodo(t, repo, datedTodo("b", "Buy milk", date))
+
+	err := repo.Batch(func(tx *Repository) error {
+		for _, todo := range []*models.Todo{&a, &b, &a} {
+			todo.SetStatus(models.StatusDone)
+			if todo.ID == "a" {
+				todo.Title = "Send the report"
+			}
+			if err := tx.UpdateTodo(todo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := repo.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Undo) != 3 || len(history.Undo[2].Changes) != 2 {
+		t.Fatalf("the batch was not recorded as one action with one change per todo: %+v", history.Undo)
+	}
+
+	if _, err := repo.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	todos, err := repo.GetTodosForDate(date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, todo := range todos {
+		if todo.Completed || todo.Title == "Send the report" {
+			t.Errorf("undoing the batch left %q completed=%v", todo.Title, todo.Completed)
+		}
+	}
+}
+
+func TestBatchFailureSavesNothing(t *testing.T) {
+	date := "2025-06-15"
+	repo, _ := newHistoryRepos()
+	a := addTodo(t, repo, datedTodo("a", "Write report", date))
+	b := addTodo(t, repo, datedTodo("b", "Buy milk", date))
+
+	failed := errors.New("failed")
+	err := repo.Batch(func(tx *Repository) error {
+		a.Title = "Send the report"
+		if err := tx.UpdateTodo(&a); err != nil {
+			return err
+		}
+		if err := tx.DeleteTodo(b); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Batch: %v, want %v", err, failed)
+	}
+
+	if got := titles(t, repo, date); !equalStrings(got, []string{"Write report", "Buy milk"}) {
+		t.Errorf("todos after the failed batch are %q", got)
+	}
+	if trash, err := repo.TrashedTodos(); err != nil || len(trash) != 0 {
+		t.Errorf("the failed batch left %d todos in the trash, %v", len(trash), err)
+	}
+	history, err := repo.History()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history.Undo) != 2 {
+		t.Errorf("the failed batch was recorded: %+v", history.Undo)
+	}
+}
+
+func TestUndoConflict(t *testing.T) {
+	date := "2025-06-15"
+	tests := []struct {
+		name    string
+		outside func(repo *Repository, todo models.Todo) error
+	}{
+		{"updated", func(repo *Repository, todo models.Todo) error {
+			todo.Title = "Changed elsewhere"
+			return repo.UpdateTodo(&todo)
+		}},
+		{"deleted", func(repo *Repository, todo models.Todo) error {
+			return repo.DeleteTodo(todo)
+		}},
+	}
+
+	for _, tt := range tests {
+		repo, outside := newHistoryRepos()
+		todo := addTodo(t, repo, datedTodo("a", "Write report", date))
+		other := addTodo(t, repo, datedTodo("b", "Buy milk", date))
+
+		// One action changing both todos, then one of them elsewhere
+		err := repo.Batch(func(tx *Repository) error {
+			for _, td := range []*models.Todo{&todo, &other} {
+				td.Title += " today"
+				if err := tx.UpdateTodo(td); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		current, err := repo.FindTodo("a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := tt.outside(outside, current); err != nil {
+			t.Fatal(err)
+		}
+		want := titles(t, repo, date)
+
+		if _, err := repo.Undo(); !errors.Is(err, ErrHistoryConflict) {
+			t.Errorf("%s: Undo: %v, want %v", tt.name, err, ErrHistoryConflict)
+		}
+		if got := titles(t, repo, date); !equalStrings(got, want) {
+			t.Errorf("%s: the failed undo changed the todos to %q, want %q", tt.name, got, want)
+		}
+		history, err := repo.History()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history.Undo) != 3 || len(history.Redo) != 0 {
+			t.Errorf("%s: the failed undo changed the history", tt.name)
+		}
+	}
+}
+
+func TestRedoConflict(t *testing.T) {
+	date := "2025-06-15"
+	repo, outside := newHistoryRepos()
+	todo := datedTodo("a", "Write report", date)
+	if err := repo.AddTodo(todo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Undo(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same todo added again elsewhere is not overwritten by the redo
+	todo.Title = "Added elsewhere"
+	if err := outside.AddTodo(todo); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Redo(); !errors.Is(err, ErrHistoryConflict) {
+		t.Errorf("Redo: %v, want %v", err, ErrHistoryConflict)
+	}
+	if got := titles(t, repo, date); !equalStrings(got, []string{"Added elsewhere"}) {
+		t.Errorf("todos are %q after the failed redo", got)
+	}
+}
+
+func TestUndoKeepsToItsSession(t *testing.T) {
+	date := "2025-06-15"
+	store := NewMemoryStore()
+	first, second := NewRepository(WithStore(store)), NewRepository(WithStore(store))
+	first.RecordHistory("first")
+	second.RecordHistory("second")
+
+	addTodo(t, first, datedTodo("a", "Write report", date))
+	addTodo(t, second, datedTodo("b", "Buy milk", date))
+	addTodo(t, first, datedTodo("c", "Plan trip", date))
+
+	// Each session undoes its own actions, newest first, whatever the other
+	// did in between
+	for _, step := range []struct {
+		repo *Repository
+		want []string
+	}{
+		{first, []string{"Write report", "Buy milk"}},
+		{first, []string{"Buy milk"}},
+	} {
+		if _, err := step.repo.Undo(); err != nil {
+			t.Fatalf("Undo in %s: %v", step.repo.Session(), err)
+		}
+		if got := titles(t, first, date); !equalStrings(got, step.want) {
+			t.Errorf("Undo in %s left %q, want %q", step.repo.Session(), got, step.want)
+		}
+	}
+	if _, err := first.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo with only actions of another running session: %v, want %v", err, ErrNothingToUndo)
+	}
+	if _, err := second.Undo(); err != nil {
+		t.Fatalf("Undo in second: %v", err)
+	}
+	if got := titles(t, first, date); len(got) != 0 {
+		t.Errorf("todos are %q after undoing everything", got)
+	}
+
+	// A new action of one session keeps what the other can redo
+	addTodo(t, first, datedTodo("d", "Call mum", date))
+	if _, err := second.Redo(); err != nil {
+		t.Errorf("Redo after another session recorded an action: %v", err)
+	}
+	if _, err := first.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Redo after a new action: %v, want %v", err, ErrNothingToRedo)
+	}
+	if got := titles(t, first, date); !equalStrings(got, []string{"Call mum", "Buy milk"}) {
+		t.Errorf("todos are %q", got)
+	}
+}
+
+func TestUndoAfterRestart(t *testing.T) {
+	date := "2025-06-15"
+	store := NewMemoryStore()
+	before := NewRepository(WithStore(store))
+	before.RecordHistory("before")
+	addTodo(t, before, datedTodo("a", "Write report", date))
+
+	// A session started later can undo what was done before it started, but
+	// not what a session still running does after
+	restarted := NewRepository(WithStore(store))
+	restarted.RecordHistory("restarted")
+	addTodo(t, before, datedTodo("b", "Buy milk", date))
+
+	entry, err := restarted.Undo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Session != "before" {
+		t.Errorf("undid an action of session %q, want the earlier one", entry.Session)
+	}
+	if got := titles(t, restarted, date); !equalStrings(got, []string{"Buy milk"}) {
+		t.Errorf("todos are %q, want the later action kept", got)
+	}
+	if _, err := restarted.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("second Undo: %v, want %v", err, ErrNothingToUndo)
+	}
+
+	// The undone action now belongs to the session that undid it
+	if _, err := before.Redo(); !errors.Is(err, ErrNothingToRedo) {
+		t.Errorf("Redo in the earlier session: %v, want %v", err, ErrNothingToRedo)
+	}
+	if _, err := restarted.Redo(); err != nil {
+		t.Errorf("Redo in the session that undid it: %v", err)
+	}
+}
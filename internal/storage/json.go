@@ -5,16 +5,38 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
+	"github.com/WasathTheekshana/tedo/internal/atomicfile"
 	"github.com/WasathTheekshana/tedo/internal/models"
 )
 
 const (
-	DataDir      = "data"
-	GeneralFile  = "general.json"
-	DatedFileExt = ".json"
+	DataDir       = "data"         // legacy location, relative to the working directory
+	GeneralFile   = "general.json" // legacy home of undated todos, now the default list
+	DatedFileExt  = ".json"
+	ListsFile     = "lists.json"     // metadata of the named lists
+	ListsDir      = "lists"          // one file per list
+	CarryOverFile = "carryover.json" // record of todos carried over from past days
+	HistoryFile   = "history.json"   // actions that can be undone and redone
 )
 
+// RecurringBucket is the named bucket holding recurring series
+const RecurringBucket = "recurring"
+
+// TrashBucket is the named bucket holding deleted todos until they are
+// restored or purged
+const TrashBucket = "trash"
+
+// DefaultListBucket is the named bucket of the default list
+var DefaultListBucket = ListBucket(models.DefaultListID)
+
+// ListBucket returns the named bucket holding the todos of a list
+func ListBucket(id string) string {
+	return ListsDir + "/" + id
+}
+
 // JSONStorage handles file-based JSON storage
 type JSONStorage struct {
 	dataDir string
@@ -22,11 +44,21 @@ type JSONStorage struct {
 
 // NewJSONStorage create a new JSON storage instance
 func NEWJSONStorage() *JSONStorage {
+	return NewJSONStorageAt(DataDir)
+}
+
+// NewJSONStorageAt creates a JSON storage instance rooted at dataDir
+func NewJSONStorageAt(dataDir string) *JSONStorage {
 	return &JSONStorage{
-		dataDir: DataDir,
+		dataDir: dataDir,
 	}
 }
 
+// DataDir returns the directory the JSON files are stored in
+func (s *JSONStorage) DataDir() string {
+	return s.dataDir
+}
+
 // ensureDataDir creates the data directory is it doesn't exist
 func (s *JSONStorage) ensureDataDir() error {
 	if _, err := os.Stat(s.dataDir); os.IsNotExist(err) {
@@ -35,53 +67,246 @@ func (s *JSONStorage) ensureDataDir() error {
 	return nil
 }
 
-// getFilePath returns the file path for a given data or general todos
+// getFilePath returns the file path for a given date or the default list
 func (s *JSONStorage) getFilePath(data *string) string {
 	if data == nil {
-		return filepath.Join(s.dataDir, GeneralFile)
+		return s.getNamedFilePath(DefaultListBucket)
 	}
 	return filepath.Join(s.dataDir, *data+DatedFileExt)
 }
 
+// getNamedFilePath returns the file path for a named bucket
+func (s *JSONStorage) getNamedFilePath(name string) string {
+	return filepath.Join(s.dataDir, filepath.FromSlash(name)+DatedFileExt)
+}
+
 // SaveTodos saves todos to the appropriate JSON file
 func (s *JSONStorage) SaveTodos(todos []models.Todo, date *string) error {
-	if err := s.ensureDataDir(); err != nil {
+	return s.writeTodos(s.getFilePath(date), todos)
+}
+
+// SaveNamed saves todos to the JSON file of a named bucket
+func (s *JSONStorage) SaveNamed(name string, todos []models.Todo) error {
+	return s.writeTodos(s.getNamedFilePath(name), todos)
+}
+
+// writeTodos atomically replaces filePath with todos, keeping backups
+func (s *JSONStorage) writeTodos(filePath string, todos []models.Todo) error {
+	todoList := models.TodoList{Version: models.CurrentVersion, Todos: todos}
+	return writeJSON(filePath, todoList)
+}
+
+// writeJSON atomically replaces filePath with v encoded as JSON, keeping backups
+func writeJSON(filePath string, v any) error {
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
 		return fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	filePath := s.getFilePath(date)
-	todoList := models.TodoList{Todos: todos}
-
-	data, err := json.MarshalIndent(todoList, "", "  ")
+	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to marshal todos: %w", err)
+		return fmt.Errorf("failed to marshal %s: %w", filepath.Base(filePath), err)
 	}
 
-	if err := os.WriteFile(filePath, data, 0o644); err != nil {
-		return fmt.Errorf("failed to write file %s: %w", filePath, err)
+	if err := rotateBackups(filePath); err != nil {
+		return err
 	}
 
-	return nil
+	return atomicfile.WriteFile(filePath, data, 0o644)
 }
 
-// LoadTodos loads todos from the appropriate JSON file
+// LoadTodos loads todos from the appropriate JSON file. If the file cannot
+// be read or parsed, the newest backup that can be is used instead.
 func (s *JSONStorage) LoadTodos(date *string) ([]models.Todo, error) {
-	filePath := s.getFilePath(date)
+	return s.readTodos(s.getFilePath(date))
+}
 
-	// If file doesn't exist, return empty slice (not an error)
+// LoadNamed loads todos from the JSON file of a named bucket
+func (s *JSONStorage) LoadNamed(name string) ([]models.Todo, error) {
+	return s.readTodos(s.getNamedFilePath(name))
+}
+
+// readTodos loads filePath, falling back to its backups if it is damaged
+func (s *JSONStorage) readTodos(filePath string) ([]models.Todo, error) {
+	todos := []models.Todo{}
+	err := readWithBackups(filePath, func(data []byte) error {
+		todoList, _, _, err := decodeTodoFile(data)
+		if err != nil {
+			return err
+		}
+		todos = todoList.Todos
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todos, nil
+}
+
+// readWithBackups passes the contents of filePath to decode. If the file
+// cannot be read or decoded, the newest backup that can be is used instead.
+// A missing file is not an error and leaves decode uncalled.
+func readWithBackups(filePath string, decode func(data []byte) error) error {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return []models.Todo{}, nil
+		return nil
 	}
 
+	err := readFile(filePath, decode)
+	if err == nil {
+		return nil
+	}
+
+	for n := 0; n < BackupCount; n++ {
+		if readFile(backupPath(filePath, n), decode) == nil {
+			return nil
+		}
+	}
+
+	return err
+}
+
+// readFile reads filePath and passes its contents to decode
+func readFile(filePath string, decode func(data []byte) error) error {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read file %s: %w", filePath, err)
+		return fmt.Errorf("failed to read file %s: %w", filePath, err)
+	}
+	if err := decode(data); err != nil {
+		return fmt.Errorf("failed to load %s: %w", filePath, err)
+	}
+	return nil
+}
+
+// checkVersion rejects a file written in a newer data format
+func checkVersion(version int) error {
+	if version > models.CurrentVersion {
+		return fmt.Errorf("data format version %d is newer than supported version %d, please upgrade tedo", version, models.CurrentVersion)
+	}
+	return nil
+}
+
+// ListDates returns the dates of all dated todo files in ascending order
+func (s *JSONStorage) ListDates() ([]string, error) {
+	entries, err := os.ReadDir(s.dataDir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data directory %s: %w", s.dataDir, err)
+	}
+
+	var dates []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, DatedFileExt) {
+			continue
+		}
+
+		date := strings.TrimSuffix(name, DatedFileExt)
+		if _, err := models.ParseDate(date); err != nil {
+			continue
+		}
+		dates = append(dates, date)
 	}
 
-	var todoList models.TodoList
-	if err := json.Unmarshal(data, &todoList); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal todos from %s: %w", filePath, err)
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// DeleteTodos removes the JSON file for a given date or the default list
+func (s *JSONStorage) DeleteTodos(date *string) error {
+	return removeFile(s.getFilePath(date))
+}
+
+// DeleteNamed removes the JSON file of a named bucket
+func (s *JSONStorage) DeleteNamed(name string) error {
+	return removeFile(s.getNamedFilePath(name))
+}
+
+// removeFile deletes filePath, ignoring files that are already gone
+func removeFile(filePath string) error {
+	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete file %s: %w", filePath, err)
 	}
+	return nil
+}
+
+// LoadLists loads the list metadata, falling back to its backups if the
+// file is damaged
+func (s *JSONStorage) LoadLists() ([]models.List, error) {
+	var lists []models.List
+	err := readWithBackups(filepath.Join(s.dataDir, ListsFile), func(data []byte) error {
+		var index models.ListIndex
+		if err := json.Unmarshal(data, &index); err != nil {
+			return err
+		}
+		if err := checkVersion(index.Version); err != nil {
+			return err
+		}
+		lists = index.Lists
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// SaveLists saves the list metadata
+func (s *JSONStorage) SaveLists(lists []models.List) error {
+	index := models.ListIndex{Version: models.CurrentVersion, Lists: lists}
+	return writeJSON(filepath.Join(s.dataDir, ListsFile), index)
+}
+
+// LoadCarryOverLog loads the record of carried todos, falling back to its
+// backups if the file is damaged
+func (s *JSONStorage) LoadCarryOverLog() (models.CarryOverLog, error) {
+	var log models.CarryOverLog
+	err := readWithBackups(filepath.Join(s.dataDir, CarryOverFile), func(data []byte) error {
+		var decoded models.CarryOverLog
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		if err := checkVersion(decoded.Version); err != nil {
+			return err
+		}
+		log = decoded
+		return nil
+	})
+	if err != nil {
+		return models.CarryOverLog{}, err
+	}
+	return log, nil
+}
+
+// SaveCarryOverLog saves the record of carried todos
+func (s *JSONStorage) SaveCarryOverLog(log models.CarryOverLog) error {
+	log.Version = models.CurrentVersion
+	return writeJSON(filepath.Join(s.dataDir, CarryOverFile), log)
+}
+
+// LoadHistory loads the undo and redo stacks, falling back to their backups
+// if the file is damaged
+func (s *JSONStorage) LoadHistory() (models.History, error) {
+	var history models.History
+	err := readWithBackups(filepath.Join(s.dataDir, HistoryFile), func(data []byte) error {
+		var decoded models.History
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			return err
+		}
+		if err := checkVersion(decoded.Version); err != nil {
+			return err
+		}
+		history = decoded
+		return nil
+	})
+	if err != nil {
+		return models.History{}, err
+	}
+	return history, nil
+}
 
-	return todoList.Todos, nil
+// SaveHistory saves the undo and redo stacks
+func (s *JSONStorage) SaveHistory(history models.History) error {
+	history.Version = models.CurrentVersion
+	return writeJSON(filepath.Join(s.dataDir, HistoryFile), history)
 }
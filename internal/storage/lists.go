@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+var (
+	// ErrListNotFound is returned when no list matches the given ID or name
+	ErrListNotFound = errors.New("list not found")
+
+	// ErrListExists is returned when a list name is already taken
+	ErrListExists = errors.New("a list with that name already exists")
+
+	// ErrListNotEmpty is returned when deleting a list that still has todos
+	ErrListNotEmpty = errors.New("list is not empty")
+
+	// ErrListArchived is returned when filing a todo in an archived list
+	ErrListArchived = errors.New("list is archived")
+
+	// ErrDefaultList is returned when archiving or deleting the default list
+	ErrDefaultList = errors.New("the default list cannot be archived or deleted")
+)
+
+// GetLists returns every list, including archived ones. The default list
+// always exists, even before any list has been saved.
+func (r *Repository) GetLists() ([]models.List, error) {
+	lists, err := r.storage.LoadLists()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load lists: %w", err)
+	}
+
+	if findList(lists, models.DefaultListID) < 0 {
+		lists = append([]models.List{models.DefaultList()}, lists...)
+	}
+	return lists, nil
+}
+
+// FindList looks up a list by its ID or, ignoring case, by its name
+func (r *Repository) FindList(ref string) (models.List, error) {
+	ref = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(ref), "@"))
+
+	lists, err := r.GetLists()
+	if err != nil {
+		return models.List{}, err
+	}
+
+	if i := findList(lists, ref); i >= 0 {
+		return lists[i], nil
+	}
+	for _, list := range lists {
+		if strings.EqualFold(list.Name, ref) {
+			return list, nil
+		}
+	}
+	return models.List{}, fmt.Errorf("%w: %s", ErrListNotFound, ref)
+}
+
+// GetListTodos returns the todos filed in a list
+func (r *Repository) GetListTodos(id string) ([]models.Todo, error) {
+	return r.storage.LoadNamed(ListBucket(id))
+}
+
+// CreateList adds a new list called name. Its ID is derived from the name.
+func (r *Repository) CreateList(name string) (models.List, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return models.List{}, errors.New("list name is required")
+	}
+
+	var list models.List
+	err := r.withLock(func() error {
+		lists, err := r.GetLists()
+		if err != nil {
+			return err
+		}
+		if nameTaken(lists, name, "") {
+			return fmt.Errorf("%w: %s", ErrListExists, name)
+		}
+
+		list = models.NewList(uniqueListID(lists, name), name)
+		return r.storage.SaveLists(append(lists, list))
+	})
+	return list, err
+}
+
+// RenameList changes the display name of a list. Its ID and file stay the
+// same, so todos do not need to move.
+func (r *Repository) RenameList(id, name string) error {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return errors.New("list name is required")
+	}
+
+	return r.withLock(func() error {
+		lists, err := r.GetLists()
+		if err != nil {
+			return err
+		}
+
+		i := findList(lists, id)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrListNotFound, id)
+		}
+		if nameTaken(lists, name, id) {
+			return fmt.Errorf("%w: %s", ErrListExists, name)
+		}
+
+		lists[i].Name = name
+		return r.storage.SaveLists(lists)
+	})
+}
+
+// SetListArchived archives or restores a list. Archived lists keep their
+// todos but no new todos can be filed in them.
+func (r *Repository) SetListArchived(id string, archived bool) error {
+	if id == models.DefaultListID {
+		return ErrDefaultList
+	}
+
+	return r.withLock(func() error {
+		lists, err := r.GetLists()
+		if err != nil {
+			return err
+		}
+
+		i := findList(lists, id)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrListNotFound, id)
+		}
+
+		lists[i].Archived = archived
+		return r.storage.SaveLists(lists)
+	})
+}
+
+// DeleteList removes an empty list
+func (r *Repository) DeleteList(id string) error {
+	if id == models.DefaultListID {
+		return ErrDefaultList
+	}
+
+	return r.withLock(func() error {
+		lists, err := r.GetLists()
+		if err != nil {
+			return err
+		}
+
+		i := findList(lists, id)
+		if i < 0 {
+			return fmt.Errorf("%w: %s", ErrListNotFound, id)
+		}
+
+		todos, err := r.GetListTodos(id)
+		if err != nil {
+			return err
+		}
+		if len(todos) > 0 {
+			return fmt.Errorf("%w: %s has %d todo(s)", ErrListNotEmpty, lists[i].Name, len(todos))
+		}
+
+		if err := r.storage.SaveLists(append(lists[:i], lists[i+1:]...)); err != nil {
+			return err
+		}
+		return r.storage.DeleteNamed(ListBucket(id))
+	})
+}
+
+// checkListWritable returns an error unless todos can be filed in the list
+// with the given ID
+func (r *Repository) checkListWritable(id string) error {
+	lists, err := r.GetLists()
+	if err != nil {
+		return err
+	}
+
+	i := findList(lists, id)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrListNotFound, id)
+	}
+	if lists[i].Archived {
+		return fmt.Errorf("%w: %s", ErrListArchived, lists[i].Name)
+	}
+	return nil
+}
+
+// findList returns the index of the list with the given ID, or -1
+func findList(lists []models.List, id string) int {
+	for i := range lists {
+		if lists[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// nameTaken reports whether a list other than except is called name
+func nameTaken(lists []models.List, name, except string) bool {
+	for _, list := range lists {
+		if list.ID != except && strings.EqualFold(list.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// uniqueListID derives an unused list ID from name
+func uniqueListID(lists []models.List, name string) string {
+	base := models.ListSlug(name)
+	id := base
+	for n := 2; findList(lists, id) >= 0; n++ {
+		id = fmt.Sprintf("%s-%d", base, n)
+	}
+	return id
+}
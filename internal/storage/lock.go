@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// LockFile is the advisory lock file created in the data directory
+	LockFile = ".lock"
+
+	// LockTimeout is how long a write waits for another tedo process to
+	// release the data directory before giving up
+	LockTimeout = 2 * time.Second
+
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+// ErrLocked is returned when another process holds the data directory lock
+var ErrLocked = errors.New("data directory is locked by another tedo process")
+
+// Locker is implemented by stores that can be shared between processes.
+// TryLock acquires an exclusive lock without blocking and returns ErrLocked
+// if it is already held elsewhere.
+type Locker interface {
+	TryLock() (unlock func() error, err error)
+}
+
+// TryLock takes the advisory lock on the data directory
+func (s *JSONStorage) TryLock() (func() error, error) {
+	if err := s.ensureDataDir(); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	path := filepath.Join(s.dataDir, LockFile)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return func() error {
+		unlockFile(f)
+		return f.Close()
+	}, nil
+}
+
+// acquireLock takes the store's lock, retrying until LockTimeout elapses.
+// Stores that do not implement Locker need no locking.
+func acquireLock(store Store) (func() error, error) {
+	locker, ok := store.(Locker)
+	if !ok {
+		return func() error { return nil }, nil
+	}
+
+	deadline := time.Now().Add(LockTimeout)
+	for {
+		unlock, err := locker.TryLock()
+		if !errors.Is(err, ErrLocked) || time.Now().After(deadline) {
+			return unlock, err
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,171 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// MemoryStore is a Store that keeps all todos in memory. It is useful for
+// tests and for embedding tedo without touching the file system.
+type MemoryStore struct {
+	mu    sync.RWMutex
+	dated map[string][]models.Todo
+	named map[string][]models.Todo
+	lists []models.List
+	carry models.CarryOverLog
+	hist  models.History
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		dated: make(map[string][]models.Todo),
+		named: make(map[string][]models.Todo),
+	}
+}
+
+// LoadTodos returns a copy of the todos in the given bucket
+func (s *MemoryStore) LoadTodos(date *string) ([]models.Todo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if date == nil {
+		return copyTodos(s.named[DefaultListBucket]), nil
+	}
+	return copyTodos(s.dated[*date]), nil
+}
+
+// SaveTodos stores a copy of todos in the given bucket
+func (s *MemoryStore) SaveTodos(todos []models.Todo, date *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if date == nil {
+		s.named[DefaultListBucket] = copyTodos(todos)
+		return nil
+	}
+	s.dated[*date] = copyTodos(todos)
+	return nil
+}
+
+// ListDates returns all dates that have a bucket, sorted ascending
+func (s *MemoryStore) ListDates() ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dates := make([]string, 0, len(s.dated))
+	for date := range s.dated {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+	return dates, nil
+}
+
+// DeleteTodos removes the given bucket
+func (s *MemoryStore) DeleteTodos(date *string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if date == nil {
+		delete(s.named, DefaultListBucket)
+		return nil
+	}
+	delete(s.dated, *date)
+	return nil
+}
+
+// LoadNamed returns a copy of the todos in a named bucket
+func (s *MemoryStore) LoadNamed(name string) ([]models.Todo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return copyTodos(s.named[name]), nil
+}
+
+// SaveNamed stores a copy of todos in a named bucket
+func (s *MemoryStore) SaveNamed(name string, todos []models.Todo) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.named[name] = copyTodos(todos)
+	return nil
+}
+
+// DeleteNamed removes a named bucket
+func (s *MemoryStore) DeleteNamed(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.named, name)
+	return nil
+}
+
+// LoadLists returns a copy of the list metadata
+func (s *MemoryStore) LoadLists() ([]models.List, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]models.List(nil), s.lists...), nil
+}
+
+// SaveLists stores a copy of the list metadata
+func (s *MemoryStore) SaveLists(lists []models.List) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lists = append([]models.List(nil), lists...)
+	return nil
+}
+
+// LoadCarryOverLog returns a copy of the record of carried todos
+func (s *MemoryStore) LoadCarryOverLog() (models.CarryOverLog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	log := s.carry
+	log.Entries = append([]models.CarriedTodo(nil), s.carry.Entries...)
+	return log, nil
+}
+
+// SaveCarryOverLog stores a copy of the record of carried todos
+func (s *MemoryStore) SaveCarryOverLog(log models.CarryOverLog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log.Entries = append([]models.CarriedTodo(nil), log.Entries...)
+	s.carry = log
+	return nil
+}
+
+// LoadHistory returns a copy of the undo and redo stacks
+func (s *MemoryStore) LoadHistory() (models.History, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	history := s.hist
+	history.Undo = append([]models.HistoryEntry(nil), s.hist.Undo...)
+	history.Redo = append([]models.HistoryEntry(nil), s.hist.Redo...)
+	return history, nil
+}
+
+// SaveHistory stores a copy of the undo and redo stacks
+func (s *MemoryStore) SaveHistory(history models.History) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	history.Undo = append([]models.HistoryEntry(nil), history.Undo...)
+	history.Redo = append([]models.HistoryEntry(nil), history.Redo...)
+	s.hist = history
+	return nil
+}
+
+// copyTodos returns a copy of todos that never aliases the original slice
+func copyTodos(todos []models.Todo) []models.Todo {
+	copied := make([]models.Todo, len(todos))
+	for i, todo := range todos {
+		copied[i] = todo.Clone()
+	}
+	return copied
+}
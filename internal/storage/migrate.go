@@ -0,0 +1,251 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/atomicfile"
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+const (
+	// legacyVersion is assumed for files written before the version field existed
+	legacyVersion = 1
+
+	// BackupDir holds copies of files made before `tedo migrate` rewrites them
+	BackupDir = "backups"
+)
+
+// Migration upgrades a decoded data file from version From to From+1
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]any) error
+}
+
+// FileMigration describes the upgrade of a single data file
+type FileMigration struct {
+	File        string
+	FromVersion int
+	Steps       []string
+}
+
+// Migrator is implemented by stores whose files can be upgraded in place
+type Migrator interface {
+	Migrate(dryRun bool) ([]FileMigration, error)
+}
+
+var migrations = map[int]Migration{}
+
+// RegisterMigration adds a migration to the registry. Registering two
+// migrations from the same version is a programming error and panics.
+func RegisterMigration(m Migration) {
+	if _, exists := migrations[m.From]; exists {
+		panic(fmt.Sprintf("storage: duplicate migration from version %d", m.From))
+	}
+	migrations[m.From] = m
+}
+
+func init() {
+	RegisterMigration(Migration{
+		From:        1,
+		Description: `rename todo field "data" to "date"`,
+		Apply:       renameTodoField("data", "date"),
+	})
+}
+
+// renameTodoField returns a migration step that renames a key on every todo
+func renameTodoField(from, to string) func(doc map[string]any) error {
+	return func(doc map[string]any) error {
+		return eachTodo(doc, func(todo map[string]any) {
+			if value, ok := todo[from]; ok {
+				if _, exists := todo[to]; !exists {
+					todo[to] = value
+				}
+				delete(todo, from)
+			}
+		})
+	}
+}
+
+// eachTodo calls fn for every todo object in a decoded document
+func eachTodo(doc map[string]any, fn func(todo map[string]any)) error {
+	raw, ok := doc["todos"]
+	if !ok || raw == nil {
+		return nil
+	}
+
+	todos, ok := raw.([]any)
+	if !ok {
+		return fmt.Errorf(`"todos" is not a list`)
+	}
+
+	for i, item := range todos {
+		todo, ok := item.(map[string]any)
+		if !ok {
+			return fmt.Errorf("todo %d is not an object", i)
+		}
+		fn(todo)
+	}
+	return nil
+}
+
+// decodeTodoFile parses a data file of any supported version, upgrading it
+// to the current format. It also returns the file's original version and the
+// descriptions of the migrations that were applied.
+func decodeTodoFile(data []byte) (models.TodoList, int, []string, error) {
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return models.TodoList{}, 0, nil, err
+	}
+
+	version := header.Version
+	if version == 0 {
+		version = legacyVersion
+	}
+
+	if err := checkVersion(version); err != nil {
+		return models.TodoList{}, version, nil, err
+	}
+
+	var todoList models.TodoList
+	if version == models.CurrentVersion {
+		err := json.Unmarshal(data, &todoList)
+		return todoList, version, nil, err
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return models.TodoList{}, version, nil, err
+	}
+
+	var steps []string
+	for v := version; v < models.CurrentVersion; v++ {
+		migration, ok := migrations[v]
+		if !ok {
+			return models.TodoList{}, version, steps, fmt.Errorf("no migration registered from version %d", v)
+		}
+		if err := migration.Apply(doc); err != nil {
+			return models.TodoList{}, version, steps, fmt.Errorf("migration from version %d failed: %w", v, err)
+		}
+		steps = append(steps, migration.Description)
+	}
+
+	upgraded, err := json.Marshal(doc)
+	if err != nil {
+		return models.TodoList{}, version, steps, err
+	}
+	if err := json.Unmarshal(upgraded, &todoList); err != nil {
+		return models.TodoList{}, version, steps, err
+	}
+
+	todoList.Version = models.CurrentVersion
+	return todoList, version, steps, nil
+}
+
+// Migrate upgrades every data file to the current format and moves the
+// legacy general file into the lists directory. With dryRun set it only
+// reports what would change. Each file is copied into a timestamped
+// directory under BackupDir before it is rewritten.
+func (s *JSONStorage) Migrate(dryRun bool) ([]FileMigration, error) {
+	var paths []string
+	for _, pattern := range []string{"*" + DatedFileExt, filepath.Join(ListsDir, "*"+DatedFileExt)} {
+		matches, err := filepath.Glob(filepath.Join(s.dataDir, pattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list data files: %w", err)
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	backupDir := filepath.Join(s.dataDir, BackupDir, "migrate-"+time.Now().Format("20060102-150405"))
+	defaultList := s.getFilePath(nil)
+
+	var results []FileMigration
+	for _, path := range paths {
+		rel, err := filepath.Rel(s.dataDir, path)
+		if err != nil {
+			return results, err
+		}
+		if rel == ListsFile || rel == CarryOverFile || rel == HistoryFile {
+			continue
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return results, fmt.Errorf("failed to read file %s: %w", path, err)
+		}
+
+		todoList, from, steps, err := decodeTodoFile(data)
+		if err != nil {
+			return results, fmt.Errorf("failed to migrate %s: %w", path, err)
+		}
+
+		// The general file becomes the default list, or is merged into it
+		// when that already exists
+		target, merge := path, false
+		if rel == GeneralFile {
+			list := filepath.ToSlash(filepath.Join(ListsDir, filepath.Base(defaultList)))
+			if _, err := os.Stat(defaultList); os.IsNotExist(err) {
+				target = defaultList
+				steps = append([]string{"move to " + list}, steps...)
+			} else {
+				target, merge = defaultList, true
+				steps = append([]string{"merge into " + list}, steps...)
+			}
+		}
+
+		if from == models.CurrentVersion && target == path {
+			continue
+		}
+
+		results = append(results, FileMigration{
+			File:        filepath.ToSlash(rel),
+			FromVersion: from,
+			Steps:       steps,
+		})
+
+		if dryRun {
+			continue
+		}
+
+		backup := filepath.Join(backupDir, rel)
+		if err := os.MkdirAll(filepath.Dir(backup), 0o755); err != nil {
+			return results, fmt.Errorf("failed to create backup directory: %w", err)
+		}
+		if err := copyFile(path, backup); err != nil {
+			return results, err
+		}
+
+		if merge {
+			if err := s.mergeGeneralFile(path); err != nil {
+				return results, err
+			}
+			continue
+		}
+		if target != path {
+			if err := moveWithBackups(path, target); err != nil {
+				return results, err
+			}
+		}
+		if from == models.CurrentVersion {
+			continue
+		}
+
+		upgraded, err := json.MarshalIndent(todoList, "", "  ")
+		if err != nil {
+			return results, fmt.Errorf("failed to marshal todos: %w", err)
+		}
+		if err := atomicfile.WriteFile(target, upgraded, 0o644); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
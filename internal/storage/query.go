@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/query"
+)
+
+// Query returns the todos of every list and date that q selects, in date
+// order. A recurring todo takes part as its occurrence today, or else its
+// next one; series that have ended are left out.
+func (r *Repository) Query(q *query.Query, now time.Time) ([]models.Todo, error) {
+	lists, err := r.GetLists()
+	if err != nil {
+		return nil, err
+	}
+
+	env := query.Env{Now: now, Lists: make(map[string]string, len(lists))}
+	var candidates []models.Todo
+	for _, list := range lists {
+		env.Lists[list.ID] = list.Name
+		todos, err := r.GetListTodos(list.ID)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, todos...)
+	}
+
+	dates, err := r.GetDates()
+	if err != nil {
+		return nil, err
+	}
+	for _, date := range dates {
+		todos, err := r.storage.LoadTodos(&date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load todos for %s: %w", date, err)
+		}
+		candidates = append(candidates, todos...)
+	}
+
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recurring todos: %w", err)
+	}
+	today := models.FormatDate(now)
+	for i := range series {
+		if series[i].OccursOn(today) {
+			candidates = append(candidates, series[i].Occurrence(today))
+		} else if next, ok := series[i].NextOccurrenceAfter(today); ok {
+			candidates = append(candidates, series[i].Occurrence(next))
+		}
+	}
+
+	selected, err := q.Filter(candidates, env)
+	if err != nil {
+		return nil, err
+	}
+	models.SortTodos(selected)
+	return selected, nil
+}
@@ -0,0 +1,242 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// ErrRecurringNeedsDate is returned when a recurring todo has no start date
+var ErrRecurringNeedsDate = errors.New("recurring todos need a start date")
+
+// GetRecurringTodos returns every recurring series
+func (r *Repository) GetRecurringTodos() ([]models.Todo, error) {
+	return r.storage.LoadNamed(RecurringBucket)
+}
+
+// appendOccurrences adds the occurrences of recurring series on date to todos
+func (r *Repository) appendOccurrences(todos []models.Todo, date string) ([]models.Todo, error) {
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recurring todos: %w", err)
+	}
+
+	for i := range series {
+		if series[i].OccursOn(date) {
+			todos = append(todos, series[i].Occurrence(date))
+		}
+	}
+	return todos, nil
+}
+
+// findSeries returns the index of a series in series, or -1
+func findSeries(series []models.Todo, id string) int {
+	for i := range series {
+		if series[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// findOccurrence looks up the occurrence on date of the series with the
+// given ID or unique prefix of it
+func (r *Repository) findOccurrence(seriesRef, date string) (models.Todo, error) {
+	series, err := r.FindTodo(seriesRef)
+	if err != nil {
+		return models.Todo{}, err
+	}
+
+	if !series.IsRecurring() || !series.OccursOn(date) {
+		return models.Todo{}, fmt.Errorf("%w: %s", ErrTodoNotFound, models.OccurrenceID(series.ID, date))
+	}
+	return series.Occurrence(date), nil
+}
+
+// addSeries stores a new recurring series; the caller must hold the lock
+func (r *Repository) addSeries(todo models.Todo) error {
+	if todo.Date == nil {
+		return ErrRecurringNeedsDate
+	}
+
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return fmt.Errorf("failed to load existing todos: %w", err)
+	}
+
+	series = append(series, todo)
+	return r.writeBucket(nil, RecurringBucket, series)
+}
+
+// updateSeries replaces a stored series; the caller must hold the lock
+func (r *Repository) updateSeries(todo models.Todo) error {
+	if todo.Date == nil {
+		return ErrRecurringNeedsDate
+	}
+
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return fmt.Errorf("failed to load existing todos: %w", err)
+	}
+
+	i := findSeries(series, todo.ID)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrTodoNotFound, todo.ID)
+	}
+
+	series[i] = todo
+	return r.writeBucket(nil, RecurringBucket, series)
+}
+
+// updateOccurrence saves changes made to one occurrence. Its completion
+// state only affects that date; title, description and rule apply to the
+// whole series. The caller must hold the lock.
+func (r *Repository) updateOccurrence(occurrence models.Todo) error {
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return fmt.Errorf("failed to load existing todos: %w", err)
+	}
+
+	i := findSeries(series, occurrence.SeriesID)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrTodoNotFound, occurrence.ID)
+	}
+
+	s := &series[i]
+	s.Title = occurrence.Title
+	s.Description = occurrence.Description
+	s.Priority = occurrence.Priority
+	s.Tags = occurrence.Tags
+	s.BlockedBy = occurrence.BlockedBy
+	s.SetOccurrenceChecklist(*occurrence.Date, occurrence.Checklist)
+	if occurrence.Recurrence != nil {
+		s.Recurrence = occurrence.Recurrence
+	}
+	s.SetOccurrenceStatus(*occurrence.Date, occurrence.Status)
+	s.UpdatedAt = occurrence.UpdatedAt
+
+	return r.writeBucket(nil, RecurringBucket, series)
+}
+
+// skipOccurrence removes a single occurrence from its series, which keeps
+// its other dates; the caller must hold the lock
+func (r *Repository) skipOccurrence(occurrence models.Todo) error {
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return fmt.Errorf("failed to load todos: %w", err)
+	}
+
+	i := findSeries(series, occurrence.SeriesID)
+	if i < 0 || occurrence.Date == nil || !series[i].OccursOn(*occurrence.Date) {
+		return fmt.Errorf("%w: %s", ErrTodoNotFound, occurrence.ID)
+	}
+
+	series[i].SkipOccurrence(*occurrence.Date)
+	return r.writeBucket(nil, RecurringBucket, series)
+}
+
+// deleteSeries removes the series with the given ID. It reports false if
+// the ID is not a recurring todo. The caller must hold the lock.
+func (r *Repository) deleteSeries(id string) (bool, error) {
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return false, fmt.Errorf("failed to load todos: %w", err)
+	}
+
+	i := findSeries(series, id)
+	if i < 0 {
+		return false, nil
+	}
+
+	series = append(series[:i], series[i+1:]...)
+	return true, r.writeBucket(nil, RecurringBucket, series)
+}
+
+// moveSeries handles moves that involve a recurring series: changing its
+// start date, turning a one-off todo into a series or a series back into a
+// one-off todo. It reports false if neither side is recurring. The caller
+// must hold the lock.
+func (r *Repository) moveSeries(todo models.Todo, from bucket) (bool, error) {
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return false, fmt.Errorf("failed to load existing todos: %w", err)
+	}
+	wasSeries := findSeries(series, todo.ID) >= 0
+
+	switch {
+	case wasSeries && todo.IsRecurring():
+		return true, r.updateSeries(todo)
+
+	case wasSeries:
+		// The repeat rule was removed, keep a single todo on the start date
+		todo.CompletedOn = nil
+		todo.SkippedOn = nil
+		if err := r.addTodo(todo); err != nil {
+			return true, err
+		}
+		_, err := r.deleteSeries(todo.ID)
+		return true, err
+
+	case todo.IsRecurring():
+		if err := r.addSeries(todo); err != nil {
+			return true, err
+		}
+		return true, r.deleteTodo(todo.ID, from)
+	}
+
+	return false, nil
+}
+
+// moveOccurrence saves an edited occurrence. Keeping its date and rule
+// edits the series. Moving it to another date detaches it into a one-off
+// todo and skips the original date. Clearing its rule ends the series
+// before this occurrence. The caller must hold the lock.
+func (r *Repository) moveOccurrence(occurrence models.Todo, date *string) error {
+	occurrenceDate := *occurrence.Date
+	if date != nil && *date == occurrenceDate && occurrence.Recurrence != nil {
+		return r.updateOccurrence(occurrence)
+	}
+
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return fmt.Errorf("failed to load existing todos: %w", err)
+	}
+
+	i := findSeries(series, occurrence.SeriesID)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrTodoNotFound, occurrence.ID)
+	}
+
+	// Add the detached todo first so a failure never loses the occurrence
+	detached := models.NewTodo(occurrence.Title, occurrence.Description, date)
+	detached.SetStatus(occurrence.CurrentStatus())
+	detached.Priority = occurrence.Priority
+	detached.Tags = occurrence.Tags
+	detached.Checklist = occurrence.Checklist
+	detached.BlockedBy = occurrence.BlockedBy
+	detached.List = occurrence.List
+	if err := r.addTodo(detached); err != nil {
+		return err
+	}
+
+	s := &series[i]
+	if occurrence.Recurrence != nil {
+		s.SkipOccurrence(occurrenceDate)
+		return r.writeBucket(nil, RecurringBucket, series)
+	}
+	s.SetOccurrenceStatus(occurrenceDate, models.StatusOpen)
+
+	// End the series the day before this occurrence
+	day, err := models.ParseDate(occurrenceDate)
+	if err != nil {
+		return fmt.Errorf("invalid occurrence date %q: %w", occurrenceDate, err)
+	}
+	until := models.FormatDate(day.AddDate(0, 0, -1))
+	if s.Date == nil || until < *s.Date {
+		series = append(series[:i], series[i+1:]...)
+	} else if s.Recurrence.Until == "" || until < s.Recurrence.Until {
+		s.Recurrence.Until = until
+	}
+	return r.writeBucket(nil, RecurringBucket, series)
+}
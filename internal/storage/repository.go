@@ -1,63 +1,220 @@
 package storage
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/WasathTheekshana/tedo/internal/models"
 )
 
+var (
+	// ErrTodoNotFound is returned when no todo matches the given ID
+	ErrTodoNotFound = errors.New("todo not found")
+
+	// ErrAmbiguousID is returned when an ID prefix matches several todos
+	ErrAmbiguousID = errors.New("todo ID is ambiguous")
+
+	// ErrTodoChanged is returned when a todo was saved again, e.g. by another
+	// tedo process, after the copy being saved was loaded
+	ErrTodoChanged = errors.New("todo was changed elsewhere since it was loaded")
+)
+
 // Repository provides high-level operations for todo management
 type Repository struct {
-	storage *JSONStorage
+	storage Store
+	mu      sync.Mutex // serializes writes within this process
+
+	// Undo history, see RecordHistory
+	session      string               // session changes are recorded for, empty when not recording
+	sessionStart time.Time            // when RecordHistory was called
+	recording    *models.HistoryEntry // action being recorded while the lock is held
+
+	locked bool // the lock is already held, as it is by the repository of a Batch
+}
+
+// Option configures a Repository
+type Option func(*Repository)
+
+// WithStore makes the repository persist todos in the given store
+func WithStore(store Store) Option {
+	return func(r *Repository) {
+		r.storage = store
+	}
+}
+
+// WithDataDir makes the repository store JSON files in dir
+func WithDataDir(dir string) Option {
+	return WithStore(NewJSONStorageAt(dir))
+}
+
+// WithMemoryStore makes the repository keep todos in memory only
+func WithMemoryStore() Option {
+	return WithStore(NewMemoryStore())
+}
+
+// NewRepository creates a new repository instance. Without options todos
+// are stored as JSON files in the data directory.
+func NewRepository(opts ...Option) *Repository {
+	r := &Repository{}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if r.storage == nil {
+		r.storage = NEWJSONStorage()
+	}
+	return r
 }
 
-// NewRepository creates a new repository instance
-func NewRepository() *Repository {
-	return &Repository{
-		storage: NEWJSONStorage(),
+// withLock runs fn while holding the data lock so that read-modify-write
+// cycles are not interleaved with other goroutines or tedo processes. When
+// history is recorded, the changes fn makes become one undoable action.
+// Within a Batch, fn simply runs as part of the batch.
+func (r *Repository) withLock(fn func() error) error {
+	return r.lockData(func() error {
+		if r.session == "" || r.locked {
+			return fn()
+		}
+		return r.record(fn)
+	})
+}
+
+// lockData runs fn while holding the data lock, without recording history
+func (r *Repository) lockData(fn func() error) error {
+	if r.locked {
+		return fn()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	unlock, err := acquireLock(r.storage)
+	if err != nil {
+		return err
 	}
+	defer unlock()
+
+	return fn()
 }
 
-// GetTodosForDate retrieves todos for a specific date
+// GetTodosForDate retrieves todos for a specific date, including the
+// occurrences of recurring todos that fall on it
 func (r *Repository) GetTodosForDate(date string) ([]models.Todo, error) {
-	return r.storage.LoadTodos(&date)
+	todos, err := r.storage.LoadTodos(&date)
+	if err != nil {
+		return nil, err
+	}
+	return r.appendOccurrences(todos, date)
 }
 
-// GetGeneralTodos retrieves general todos (no specific date)
+// GetGeneralTodos retrieves the todos of the default list
 func (r *Repository) GetGeneralTodos() ([]models.Todo, error) {
-	return r.storage.LoadTodos(nil)
+	return r.GetListTodos(models.DefaultListID)
+}
+
+// bucket identifies where a todo is stored: the file of its date or, for
+// undated todos, the file of its list
+type bucket struct {
+	date *string
+	list string
+}
+
+// bucketOf returns the bucket todo is stored in
+func bucketOf(todo models.Todo) bucket {
+	if todo.Date != nil {
+		return bucket{date: todo.Date}
+	}
+	return bucket{list: todo.ListID()}
+}
+
+// same reports whether two buckets refer to the same file
+func (b bucket) same(other bucket) bool {
+	if b.date == nil || other.date == nil {
+		return b.date == nil && other.date == nil && b.list == other.list
+	}
+	return *b.date == *other.date
+}
+
+// loadBucket returns the todos stored in b
+func (r *Repository) loadBucket(b bucket) ([]models.Todo, error) {
+	if b.date != nil {
+		return r.storage.LoadTodos(b.date)
+	}
+	return r.storage.LoadNamed(ListBucket(b.list))
+}
+
+// saveBucket replaces the todos stored in b
+func (r *Repository) saveBucket(b bucket, todos []models.Todo) error {
+	if b.date != nil {
+		return r.writeBucket(b.date, "", todos)
+	}
+	return r.writeBucket(nil, ListBucket(b.list), todos)
 }
 
 // AddTodo adds a new todo and saves it
 func (r *Repository) AddTodo(todo models.Todo) error {
-	var todos []models.Todo
-	var err error
+	return r.withLock(func() error {
+		return r.addTodo(todo)
+	})
+}
 
-	if todo.IsGeneral() {
-		todos, err = r.GetGeneralTodos()
-	} else {
-		todos, err = r.GetTodosForDate(*todo.Date)
+// addTodo appends todo to its bucket; the caller must hold the lock
+func (r *Repository) addTodo(todo models.Todo) error {
+	stamp(&todo)
+	if todo.IsRecurring() {
+		return r.addSeries(todo)
 	}
 
+	b := bucketOf(todo)
+	if b.date == nil {
+		if err := r.checkListWritable(b.list); err != nil {
+			return err
+		}
+	}
+
+	todos, err := r.loadBucket(b)
 	if err != nil {
 		return fmt.Errorf("failed to load existing todos: %w", err)
 	}
 
 	todos = append(todos, todo)
-	return r.storage.SaveTodos(todos, todo.Date)
+	return r.saveBucket(b, todos)
 }
 
-// UpdateTodo updates an existing todo
-func (r *Repository) UpdateTodo(updatedTodo models.Todo) error {
-	var todos []models.Todo
-	var err error
+// UpdateTodo saves the changes made to todo and, once saved, updates todo
+// to the stored version. If the todo was saved elsewhere since todo was
+// loaded, nothing is written and ErrTodoChanged is returned.
+func (r *Repository) UpdateTodo(todo *models.Todo) error {
+	return r.withLock(func() error {
+		updated := *todo
+		if err := r.updateTodo(&updated); err != nil {
+			return err
+		}
+		*todo = updated
+		return nil
+	})
+}
 
-	if updatedTodo.IsGeneral() {
-		todos, err = r.GetGeneralTodos()
-	} else {
-		todos, err = r.GetTodosForDate(*updatedTodo.Date)
+// updateTodo replaces a todo in its bucket and stamps updatedTodo; the
+// caller must hold the lock
+func (r *Repository) updateTodo(updatedTodo *models.Todo) error {
+	if err := r.checkUnchanged(*updatedTodo); err != nil {
+		return err
 	}
 
+	stamp(updatedTodo)
+	if updatedTodo.IsOccurrence() {
+		return r.updateOccurrence(*updatedTodo)
+	}
+	if updatedTodo.IsRecurring() {
+		return r.updateSeries(*updatedTodo)
+	}
+
+	b := bucketOf(*updatedTodo)
+	todos, err := r.loadBucket(b)
 	if err != nil {
 		return fmt.Errorf("failed to load existing todos: %w", err)
 	}
@@ -66,22 +223,79 @@ func (r *Repository) UpdateTodo(updatedTodo models.Todo) error {
 	found := false
 	for i, todo := range todos {
 		if todo.ID == updatedTodo.ID {
-			todos[i] = updatedTodo
+			todos[i] = *updatedTodo
 			found = true
 			break
 		}
 	}
 
 	if !found {
-		return fmt.Errorf("todo with ID %s not found", updatedTodo.ID)
+		return fmt.Errorf("%w: %s", ErrTodoNotFound, updatedTodo.ID)
+	}
+
+	return r.saveBucket(b, todos)
+}
+
+// checkUnchanged returns ErrTodoChanged if the stored version of todo, or
+// the series of an occurrence, was saved after todo was loaded. A todo that
+// is not stored passes; saving it reports that. The caller must hold the
+// lock.
+func (r *Repository) checkUnchanged(todo models.Todo) error {
+	id := todo.ID
+	if todo.IsOccurrence() {
+		id = todo.SeriesID
+	}
+
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return fmt.Errorf("failed to load existing todos: %w", err)
+	}
+	stored := series
+	i := findSeries(series, id)
+	if i < 0 && !todo.IsOccurrence() {
+		if stored, err = r.loadBucket(bucketOf(todo)); err != nil {
+			return fmt.Errorf("failed to load existing todos: %w", err)
+		}
+		i = findTodo(stored, id)
 	}
 
-	return r.storage.SaveTodos(todos, updatedTodo.Date)
+	if i >= 0 && !sameTime(stored[i].UpdatedAt, todo.UpdatedAt) {
+		return fmt.Errorf("%w: %s", ErrTodoChanged, todo.Title)
+	}
+	return nil
 }
 
-// DeleteTodo removes a todo
-func (r *Repository) DeleteTodo(todoID string, date *string) error {
-	todos, err := r.storage.LoadTodos(date)
+// sameTime reports whether two optional timestamps are the same instant
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// stamp records when todo is saved and when it was done or cancelled, and
+// brings its status in line with Completed. The caller saves todo next.
+func stamp(todo *models.Todo) {
+	now := time.Now()
+	todo.NormalizeStatus()
+	todo.UpdatedAt = &now
+
+	switch {
+	case !todo.Completed:
+		todo.CompletedAt = nil
+	case todo.CompletedAt == nil:
+		todo.CompletedAt = &now
+	}
+}
+
+// deleteTodo removes a todo, or a whole recurring series, from a bucket;
+// the caller must hold the lock
+func (r *Repository) deleteTodo(todoID string, b bucket) error {
+	if deleted, err := r.deleteSeries(todoID); deleted || err != nil {
+		return err
+	}
+
+	todos, err := r.loadBucket(b)
 	if err != nil {
 		return fmt.Errorf("failed to load todos: %w", err)
 	}
@@ -97,10 +311,68 @@ func (r *Repository) DeleteTodo(todoID string, date *string) error {
 	}
 
 	if !found {
-		return fmt.Errorf("todo with ID %s not found", todoID)
+		return fmt.Errorf("%w: %s", ErrTodoNotFound, todoID)
 	}
 
-	return r.storage.SaveTodos(todos, date)
+	// Empty day files are dropped so they do not linger in the data directory
+	return r.saveBucket(b, todos)
+}
+
+// MoveTodo moves a todo from its current bucket to the bucket for date.
+// A nil date files it in its list. Other changes made to todo are saved
+// along with the move.
+func (r *Repository) MoveTodo(todo models.Todo, date *string) error {
+	return r.withLock(func() error {
+		to := bucket{date: date}
+		if date == nil {
+			to.list = todo.ListID()
+		}
+		return r.moveTodo(todo, to)
+	})
+}
+
+// MoveTodoToList files a todo in the list with the given ID, removing its
+// date if it had one
+func (r *Repository) MoveTodoToList(todo models.Todo, listID string) error {
+	return r.withLock(func() error {
+		return r.moveTodo(todo, bucket{list: listID})
+	})
+}
+
+// moveTodo moves a todo between buckets; the caller must hold the lock
+func (r *Repository) moveTodo(todo models.Todo, to bucket) error {
+	if err := r.checkUnchanged(todo); err != nil {
+		return err
+	}
+	if to.date == nil {
+		if err := r.checkListWritable(to.list); err != nil {
+			return err
+		}
+	}
+
+	// Only undated todos belong to a list; the default list is left implicit
+	from := bucketOf(todo)
+	todo.SetList(to.list)
+
+	if todo.IsOccurrence() {
+		return r.moveOccurrence(todo, to.date)
+	}
+
+	todo.Date = to.date
+
+	if moved, err := r.moveSeries(todo, from); moved || err != nil {
+		return err
+	}
+
+	if from.same(to) {
+		return r.updateTodo(&todo)
+	}
+
+	// Add before deleting so a failure part-way leaves a duplicate, never a loss
+	if err := r.addTodo(todo); err != nil {
+		return err
+	}
+	return r.deleteTodo(todo.ID, from)
 }
 
 // GetTodoCountForDate returns the number of todos for a specific date
@@ -111,3 +383,159 @@ func (r *Repository) GetTodoCountForDate(date string) (int, error) {
 	}
 	return len(todos), nil
 }
+
+// GetDates returns every date that has a todo file, in ascending order
+func (r *Repository) GetDates() ([]string, error) {
+	return r.storage.ListDates()
+}
+
+// UpcomingTodos returns the todos dated after today, up to days ahead or
+// without limit when days is 0. Dated todos are found from the date files
+// that exist. Recurring todos occur on every day within the horizon; without
+// one only their next occurrence is included.
+func (r *Repository) UpcomingTodos(today string, days int) ([]models.Todo, error) {
+	last := ""
+	if days > 0 {
+		day, err := models.ParseDate(today)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q: %w", today, err)
+		}
+		last = models.FormatDate(day.AddDate(0, 0, days))
+	}
+
+	dates, err := r.GetDates()
+	if err != nil {
+		return nil, err
+	}
+
+	var upcoming []models.Todo
+	for _, date := range dates {
+		if date <= today {
+			continue
+		}
+		if last != "" && date > last {
+			break
+		}
+
+		todos, err := r.storage.LoadTodos(&date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load todos for %s: %w", date, err)
+		}
+		upcoming = append(upcoming, todos...)
+	}
+
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recurring todos: %w", err)
+	}
+	for i := range series {
+		if last == "" {
+			if next, ok := series[i].NextOccurrenceAfter(today); ok {
+				upcoming = append(upcoming, series[i].Occurrence(next))
+			}
+			continue
+		}
+		for _, date := range series[i].OccurrencesBetween(today, last) {
+			upcoming = append(upcoming, series[i].Occurrence(date))
+		}
+	}
+
+	models.SortTodos(upcoming)
+	return upcoming, nil
+}
+
+// GetAllTodos returns the todos of every list, then recurring series, then
+// every dated todo in date order. Occurrences of series are not expanded.
+func (r *Repository) GetAllTodos() ([]models.Todo, error) {
+	lists, err := r.GetLists()
+	if err != nil {
+		return nil, err
+	}
+
+	var all []models.Todo
+	for _, list := range lists {
+		todos, err := r.GetListTodos(list.ID)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, todos...)
+	}
+
+	series, err := r.GetRecurringTodos()
+	if err != nil {
+		return nil, err
+	}
+	all = append(all, series...)
+
+	dates, err := r.GetDates()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, date := range dates {
+		todos, err := r.storage.LoadTodos(&date)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load todos for %s: %w", date, err)
+		}
+		all = append(all, todos...)
+	}
+	return all, nil
+}
+
+// FindTodo looks up a todo by its ID or by a unique prefix of it. An
+// occurrence is looked up as SERIES@DATE, where SERIES may be a prefix too.
+func (r *Repository) FindTodo(id string) (models.Todo, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return models.Todo{}, fmt.Errorf("%w: empty ID", ErrTodoNotFound)
+	}
+
+	if seriesID, date, ok := models.SplitOccurrenceID(id); ok {
+		return r.findOccurrence(seriesID, date)
+	}
+
+	all, err := r.GetAllTodos()
+	if err != nil {
+		return models.Todo{}, err
+	}
+
+	var matches []models.Todo
+	for _, todo := range all {
+		if todo.ID == id {
+			return todo, nil
+		}
+		if strings.HasPrefix(todo.ID, id) {
+			matches = append(matches, todo)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return models.Todo{}, fmt.Errorf("%w: %s", ErrTodoNotFound, id)
+	case 1:
+		return matches[0], nil
+	default:
+		return models.Todo{}, fmt.Errorf("%w: %s matches %d todos", ErrAmbiguousID, id, len(matches))
+	}
+}
+
+// Migrate upgrades stored files to the current data format. Stores without
+// an on-disk format have nothing to migrate.
+func (r *Repository) Migrate(dryRun bool) ([]FileMigration, error) {
+	migrator, ok := r.storage.(Migrator)
+	if !ok {
+		return nil, nil
+	}
+
+	if dryRun {
+		return migrator.Migrate(true)
+	}
+
+	var results []FileMigration
+	err := r.withLock(func() error {
+		var err error
+		results, err = migrator.Migrate(false)
+		return err
+	})
+	return results, err
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+func TestUpdateTodoChangedElsewhere(t *testing.T) {
+	date, tomorrow := "2025-06-15", "2025-06-16"
+	repo, outside := newHistoryRepos()
+	loaded := addTodo(t, repo, datedTodo("a", "Write report", date))
+
+	elsewhere := loaded
+	elsewhere.Title = "Changed elsewhere"
+	if err := outside.UpdateTodo(&elsewhere); err != nil {
+		t.Fatal(err)
+	}
+
+	stale := loaded
+	stale.Title = "Send the report"
+	if err := repo.UpdateTodo(&stale); !errors.Is(err, ErrTodoChanged) {
+		t.Errorf("UpdateTodo of a stale copy: %v, want %v", err, ErrTodoChanged)
+	}
+	if stale.UpdatedAt != loaded.UpdatedAt {
+		t.Error("a failed UpdateTodo changed the todo it was given")
+	}
+	if err := repo.MoveTodo(loaded, &tomorrow); !errors.Is(err, ErrTodoChanged) {
+		t.Errorf("MoveTodo of a stale copy: %v, want %v", err, ErrTodoChanged)
+	}
+	if got := titles(t, repo, date); !equalStrings(got, []string{"Changed elsewhere"}) {
+		t.Errorf("todos are %q, want the change made elsewhere", got)
+	}
+
+	// The copy given to UpdateTodo is the stored one afterwards, so it can be
+	// saved again
+	current, err := repo.FindTodo("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"Send the report", "Send the final report"} {
+		current.Title = title
+		if err := repo.UpdateTodo(&current); err != nil {
+			t.Fatalf("UpdateTodo of the stored copy: %v", err)
+		}
+	}
+	if err := repo.MoveTodo(current, &tomorrow); err != nil {
+		t.Fatalf("MoveTodo of the stored copy: %v", err)
+	}
+	if got := titles(t, repo, tomorrow); !equalStrings(got, []string{"Send the final report"}) {
+		t.Errorf("todos are %q after the move", got)
+	}
+}
+
+func TestUpdateOccurrenceChangedElsewhere(t *testing.T) {
+	date := "2025-06-15"
+	repo, outside := newHistoryRepos()
+	series := datedTodo("s", "Standup", date)
+	series.Recurrence = &models.Recurrence{Frequency: models.Daily}
+	if err := repo.AddTodo(series); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := repo.FindTodo(models.OccurrenceID("s", date))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := repo.FindTodo(models.OccurrenceID("s", "2025-06-16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first.SetStatus(models.StatusDone)
+	if err := outside.UpdateTodo(&first); err != nil {
+		t.Fatal(err)
+	}
+	second.SetStatus(models.StatusDone)
+	if err := repo.UpdateTodo(&second); !errors.Is(err, ErrTodoChanged) {
+		t.Errorf("UpdateTodo of an occurrence of a series saved elsewhere: %v, want %v", err, ErrTodoChanged)
+	}
+}
@@ -0,0 +1,53 @@
+package storage
+
+import "github.com/WasathTheekshana/tedo/internal/models"
+
+// Store is the persistence backend used by Repository. Todos are grouped
+// into buckets: one per date (YYYY-MM-DD) plus the default list (nil date).
+// Collections that are not tied to a date, such as other lists and recurring
+// series, live in named buckets.
+type Store interface {
+	// LoadTodos returns the todos in a bucket, or an empty slice if the
+	// bucket does not exist yet
+	LoadTodos(date *string) ([]models.Todo, error)
+
+	// SaveTodos replaces the contents of a bucket
+	SaveTodos(todos []models.Todo, date *string) error
+
+	// ListDates returns the dates of all dated buckets in ascending order
+	ListDates() ([]string, error)
+
+	// DeleteTodos removes a bucket entirely. Deleting a missing bucket is not an error
+	DeleteTodos(date *string) error
+
+	// LoadNamed returns the todos in a named bucket, or an empty slice if it
+	// does not exist yet
+	LoadNamed(name string) ([]models.Todo, error)
+
+	// SaveNamed replaces the contents of a named bucket
+	SaveNamed(name string, todos []models.Todo) error
+
+	// DeleteNamed removes a named bucket. Deleting a missing bucket is not an error
+	DeleteNamed(name string) error
+
+	// LoadLists returns the metadata of the named lists, or nil if none
+	// have been saved yet
+	LoadLists() ([]models.List, error)
+
+	// SaveLists replaces the metadata of the named lists
+	SaveLists(lists []models.List) error
+
+	// LoadCarryOverLog returns the record of todos carried over from past
+	// days, empty if nothing was carried yet
+	LoadCarryOverLog() (models.CarryOverLog, error)
+
+	// SaveCarryOverLog replaces the record of carried todos
+	SaveCarryOverLog(log models.CarryOverLog) error
+
+	// LoadHistory returns the undo and redo stacks, empty if nothing was
+	// recorded yet
+	LoadHistory() (models.History, error)
+
+	// SaveHistory replaces the undo and redo stacks
+	SaveHistory(history models.History) error
+}
@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// DeleteTodo moves a todo to the trash, where it keeps its date or list so
+// it can be restored there. Deleting an occurrence of a recurring todo only
+// skips its date; the series goes to the trash when it is deleted itself.
+func (r *Repository) DeleteTodo(todo models.Todo) error {
+	return r.withLock(func() error {
+		if todo.IsOccurrence() {
+			return r.skipOccurrence(todo)
+		}
+		return r.trashTodo(todo)
+	})
+}
+
+// trashTodo moves the stored version of todo to the trash; the caller must
+// hold the lock
+func (r *Repository) trashTodo(todo models.Todo) error {
+	stored, err := r.storedTodo(todo)
+	if err != nil {
+		return err
+	}
+
+	trash, err := r.storage.LoadNamed(TrashBucket)
+	if err != nil {
+		return fmt.Errorf("failed to load trash: %w", err)
+	}
+
+	// Add before deleting so a failure part-way leaves a duplicate, never a loss
+	now := time.Now()
+	stored.DeletedAt = &now
+	if err := r.writeBucket(nil, TrashBucket, append(trash, stored)); err != nil {
+		return err
+	}
+	return r.deleteTodo(stored.ID, bucketOf(stored))
+}
+
+// storedTodo returns the saved version of todo or of a recurring series
+func (r *Repository) storedTodo(todo models.Todo) (models.Todo, error) {
+	if todo.IsRecurring() {
+		series, err := r.GetRecurringTodos()
+		if err != nil {
+			return models.Todo{}, fmt.Errorf("failed to load todos: %w", err)
+		}
+		if i := findSeries(series, todo.ID); i >= 0 {
+			return series[i], nil
+		}
+		return models.Todo{}, fmt.Errorf("%w: %s", ErrTodoNotFound, todo.ID)
+	}
+
+	todos, err := r.loadBucket(bucketOf(todo))
+	if err != nil {
+		return models.Todo{}, fmt.Errorf("failed to load todos: %w", err)
+	}
+	if i := findTodo(todos, todo.ID); i >= 0 {
+		return todos[i], nil
+	}
+	return models.Todo{}, fmt.Errorf("%w: %s", ErrTodoNotFound, todo.ID)
+}
+
+// TrashedTodos returns the todos in the trash, most recently deleted first
+func (r *Repository) TrashedTodos() ([]models.Todo, error) {
+	trash, err := r.storage.LoadNamed(TrashBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(trash, func(i, j int) bool {
+		return deletedAt(trash[i]).After(deletedAt(trash[j]))
+	})
+	return trash, nil
+}
+
+// deletedAt returns when todo was moved to the trash
+func deletedAt(todo models.Todo) time.Time {
+	if todo.DeletedAt == nil {
+		return time.Time{}
+	}
+	return *todo.DeletedAt
+}
+
+// findTrashed returns the index of the trashed todo with the ID, or a
+// unique prefix of it, that ref names. An occurrence ID names its series.
+func findTrashed(trash []models.Todo, ref string) (int, error) {
+	ref = strings.TrimSpace(ref)
+	if seriesID, _, ok := models.SplitOccurrenceID(ref); ok {
+		ref = seriesID
+	}
+	if ref == "" {
+		return -1, fmt.Errorf("%w: empty ID", ErrTodoNotFound)
+	}
+
+	match := -1
+	for i := range trash {
+		if trash[i].ID == ref {
+			return i, nil
+		}
+		if strings.HasPrefix(trash[i].ID, ref) {
+			if match >= 0 {
+				return -1, fmt.Errorf("%w: %s matches several todos in the trash", ErrAmbiguousID, ref)
+			}
+			match = i
+		}
+	}
+	if match < 0 {
+		return -1, fmt.Errorf("%w in trash: %s", ErrTodoNotFound, ref)
+	}
+	return match, nil
+}
+
+// RestoreTodo moves a todo out of the trash back to its date or list and
+// returns it. Todos of a list that was deleted since go to the default list.
+func (r *Repository) RestoreTodo(ref string) (models.Todo, error) {
+	var restored models.Todo
+	err := r.withLock(func() error {
+		trash, err := r.storage.LoadNamed(TrashBucket)
+		if err != nil {
+			return fmt.Errorf("failed to load trash: %w", err)
+		}
+
+		i, err := findTrashed(trash, ref)
+		if err != nil {
+			return err
+		}
+
+		restored = trash[i]
+		restored.DeletedAt = nil
+		if restored.Date == nil {
+			if err := r.checkListWritable(restored.ListID()); errors.Is(err, ErrListNotFound) {
+				restored.SetList(models.DefaultListID)
+			}
+		}
+
+		// Add before removing so a failure part-way leaves a duplicate, never a loss
+		if err := r.addTodo(restored); err != nil {
+			return err
+		}
+		return r.writeBucket(nil, TrashBucket, append(trash[:i:i], trash[i+1:]...))
+	})
+	return restored, err
+}
+
+// PurgeTodo permanently deletes a todo from the trash and returns it
+func (r *Repository) PurgeTodo(ref string) (models.Todo, error) {
+	var purged models.Todo
+	err := r.withLock(func() error {
+		trash, err := r.storage.LoadNamed(TrashBucket)
+		if err != nil {
+			return fmt.Errorf("failed to load trash: %w", err)
+		}
+
+		i, err := findTrashed(trash, ref)
+		if err != nil {
+			return err
+		}
+
+		purged = trash[i]
+		return r.writeBucket(nil, TrashBucket, append(trash[:i:i], trash[i+1:]...))
+	})
+	return purged, err
+}
+
+// EmptyTrash permanently deletes every todo in the trash and returns how
+// many there were
+func (r *Repository) EmptyTrash() (int, error) {
+	var emptied int
+	err := r.withLock(func() error {
+		trash, err := r.storage.LoadNamed(TrashBucket)
+		if err != nil {
+			return fmt.Errorf("failed to load trash: %w", err)
+		}
+
+		emptied = len(trash)
+		return r.writeBucket(nil, TrashBucket, []models.Todo{})
+	})
+	return emptied, err
+}
+
+// PurgeTrash permanently deletes the todos that were moved to the trash
+// before cutoff and returns how many it deleted. Purging by age is
+// housekeeping, so it is never recorded in the undo history.
+func (r *Repository) PurgeTrash(cutoff time.Time) (int, error) {
+	var purged int
+	err := r.lockData(func() error {
+		trash, err := r.storage.LoadNamed(TrashBucket)
+		if err != nil {
+			return fmt.Errorf("failed to load trash: %w", err)
+		}
+
+		kept := trash[:0]
+		for _, todo := range trash {
+			if deletedAt(todo).Before(cutoff) {
+				purged++
+			} else {
+				kept = append(kept, todo)
+			}
+		}
+		if purged == 0 {
+			return nil
+		}
+		return r.writeBucket(nil, TrashBucket, kept)
+	})
+	return purged, err
+}
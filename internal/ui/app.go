@@ -1,12 +1,14 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/WasathTheekshana/tedo/internal/config"
 	"github.com/WasathTheekshana/tedo/internal/models"
 	"github.com/WasathTheekshana/tedo/internal/storage"
 )
@@ -19,6 +21,9 @@ const (
 	UpcomingView
 	CalendarView
 	GeneralView
+	OverdueView
+	SavedView // one of the saved views, see Model.currentSaved
+	TrashView
 )
 
 // Pagination for the app
@@ -34,7 +39,9 @@ type Model struct {
 	// View states
 	todayTodos    []models.Todo
 	upcomingTodos []models.Todo
+	overdueTodos  []models.Todo
 	generalTodos  []models.Todo
+	trashTodos    []models.Todo
 	selectedDate  string
 	cursor        int
 	calendarState CalendarState
@@ -42,7 +49,44 @@ type Model struct {
 	// Pagination
 	todayPage    int
 	upcomingPage int
+	overduePage  int
 	generalPage  int
+	trashPage    int
+
+	// Saved views shown as extra tabs
+	savedViews   []savedView
+	currentSaved int // index of the saved view shown on the SavedView tab
+
+	// Lists on the General tab
+	lists        []listEntry
+	currentList  string // ID of the open list, empty while picking one
+	listCursor   int
+	showArchived bool
+
+	// Filters applied to every list view
+	tagFilter []string // todos must carry all of these tags
+	search    string   // todos must fuzzy-match every word of this
+	searching bool     // the search is being typed
+
+	// Visual mode, where several todos are marked and acted on together
+	visual bool
+	marked map[string]bool // IDs of the marked todos
+
+	// Checklist of the todo under the cursor
+	expandedID  string // ID of the todo whose checklist is shown
+	checkCursor int
+
+	// Dependencies between todos
+	deps         storage.Dependencies
+	linkingID    string // ID of the todo whose blocker is being picked
+	linkingTitle string
+
+	// Settings and the day being shown as today
+	cfg   config.Config
+	today string // day the todos were last loaded for
+
+	// Dialogs waiting for an answer, the first one is shown
+	dialogs []dialog
 
 	// Input state
 	inputState InputState
@@ -58,23 +102,28 @@ type Model struct {
 	lastRefresh time.Time
 }
 
-// NewModel creates a new application model
+// NewModel creates a new application model backed by repo
 
-func NewModel() Model {
-	repo := storage.NewRepository()
+func NewModel(repo *storage.Repository, cfg config.Config) Model {
 	today := models.TodayString()
 
 	// Load initial data
-	todayTodos, _ := repo.GetTodosForDate(today)
-	upcomingTodos := loadUpcomingTodos(repo, today)
-	generalTodos, _ := repo.GetGeneralTodos()
+	todayTodos := loadDayTodos(repo, today)
+	upcomingTodos := loadUpcomingTodos(repo, today, cfg.UpcomingDays)
+	overdueTodos := loadOverdueTodos(repo, today)
+	lists := loadLists(repo)
+	deps := loadDependencies(repo)
 
-	return Model{
+	m := Model{
 		currentView:   TodayView,
 		repository:    repo,
 		todayTodos:    todayTodos,
 		upcomingTodos: upcomingTodos,
-		generalTodos:  generalTodos,
+		overdueTodos:  overdueTodos,
+		savedViews:    loadSavedViews(repo, cfg.Views),
+		trashTodos:    loadTrashedTodos(repo),
+		lists:         lists,
+		deps:          deps,
 		selectedDate:  today,
 		cursor:        0,
 		calendarState: NewCalendarState(),
@@ -84,49 +133,134 @@ func NewModel() Model {
 		inputState:    NewInputState(),
 		errorState:    ErrorState{},
 		lastRefresh:   time.Now(),
+		cfg:           cfg,
+		today:         today,
 	}
-}
 
-// loadUpcomingTodos loads all todos that are not for today (future dates)
-func loadUpcomingTodos(repo *storage.Repository, today string) []models.Todo {
-	// For now, we'll load todos from the next 30 days
-	// In a real app, you might want to scan the data directory
-	var upcomingTodos []models.Todo
+	m.applyCarryOver()
+	m.purgeTrash()
+	return m
+}
 
-	todayTime, _ := time.Parse("2006-01-02", today)
+// loadUpcomingTodos loads the todos planned after today, up to days ahead
+// or everything when days is 0
+func loadUpcomingTodos(repo *storage.Repository, today string, days int) []models.Todo {
+	todos, _ := repo.UpcomingTodos(today, days)
+	return todos
+}
 
-	for i := 1; i <= 30; i++ {
-		futureDate := todayTime.AddDate(0, 0, i)
-		futureDateStr := futureDate.Format("2006-01-02")
+// loadDayTodos loads the todos for date in display order
+func loadDayTodos(repo *storage.Repository, date string) []models.Todo {
+	todos, _ := repo.GetTodosForDate(date)
+	models.SortTodos(todos)
+	return todos
+}
 
-		todos, err := repo.GetTodosForDate(futureDateStr)
-		if err == nil && len(todos) > 0 {
-			upcomingTodos = append(upcomingTodos, todos...)
+// listTodos returns every todo of the current list view, ignoring filters.
+// The calendar and the list picker have none.
+func (m Model) listTodos() []models.Todo {
+	switch m.currentView {
+	case TodayView:
+		return m.todayTodos
+	case UpcomingView:
+		return m.upcomingTodos
+	case OverdueView:
+		return m.overdueTodos
+	case GeneralView:
+		if m.currentList == "" {
+			return nil
+		}
+		return m.generalTodos
+	case SavedView:
+		if view := m.currentSavedView(); view != nil {
+			return view.todos
 		}
+		return nil
+	case TrashView:
+		return m.trashTodos
+	default:
+		return nil
 	}
-
-	return upcomingTodos
 }
 
-// getPaginatedTodos returns the todos for the current page
-func (m Model) getPaginatedTodos() ([]models.Todo, int, int) {
-	var todos []models.Todo
-	var currentPage int
-
+// viewPage returns the page of the current list view, or nil for the calendar
+func (m *Model) viewPage() *int {
 	switch m.currentView {
 	case TodayView:
-		todos = m.todayTodos
-		currentPage = m.todayPage
+		return &m.todayPage
 	case UpcomingView:
-		todos = m.upcomingTodos
-		currentPage = m.upcomingPage
+		return &m.upcomingPage
+	case OverdueView:
+		return &m.overduePage
 	case GeneralView:
-		todos = m.generalTodos
-		currentPage = m.generalPage
+		return &m.generalPage
+	case SavedView:
+		if view := m.currentSavedView(); view != nil {
+			return &view.page
+		}
+		return nil
+	case TrashView:
+		return &m.trashPage
 	default:
+		return nil
+	}
+}
+
+// loadedTodos returns every loaded todo slice, so a change to one todo can
+// be shown in all the views listing it
+func (m Model) loadedTodos() [][]models.Todo {
+	loaded := [][]models.Todo{m.todayTodos, m.overdueTodos, m.upcomingTodos, m.generalTodos}
+	for _, view := range m.savedViews {
+		loaded = append(loaded, view.todos)
+	}
+	return loaded
+}
+
+// matchesFilters reports whether todo passes the active list filters
+func (m Model) matchesFilters(todo models.Todo) bool {
+	return todo.HasTags(m.tagFilter) && m.matchesSearch(todo)
+}
+
+// visibleIndices returns the positions in listTodos of the todos that pass
+// the active filters, in display order
+func (m Model) visibleIndices() []int {
+	todos := m.listTodos()
+	indices := make([]int, 0, len(todos))
+	for i, todo := range todos {
+		if m.matchesFilters(todo) {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// visibleTodos returns the todos of the current list view that pass the
+// active filters. Pagination and the cursor work on this list.
+func (m Model) visibleTodos() []models.Todo {
+	todos := m.listTodos()
+	indices := m.visibleIndices()
+	visible := make([]models.Todo, len(indices))
+	for i, index := range indices {
+		visible[i] = todos[index]
+	}
+	return visible
+}
+
+// isFiltered reports whether any list filter is active
+func (m Model) isFiltered() bool {
+	return len(m.tagFilter) > 0 || m.search != ""
+}
+
+// getPaginatedTodos returns the todos for the current page
+func (m Model) getPaginatedTodos() ([]models.Todo, int, int) {
+	page := m.viewPage()
+	if page == nil {
 		return []models.Todo{}, 0, 0
 	}
 
+	todos := m.visibleTodos()
+	currentPage := *page
+
 	totalPages := (len(todos) + TodosPerPage - 1) / TodosPerPage
 	if totalPages == 0 {
 		totalPages = 1
@@ -147,53 +281,24 @@ func (m Model) getPaginatedTodos() ([]models.Todo, int, int) {
 
 // getAbsoluteCursor returns the absolute cursor position (across all pages)
 func (m Model) getAbsoluteCursor() int {
-	switch m.currentView {
-	case TodayView:
-		return m.todayPage*TodosPerPage + m.cursor
-	case UpcomingView:
-		return m.upcomingPage*TodosPerPage + m.cursor
-	case GeneralView:
-		return m.generalPage*TodosPerPage + m.cursor
-	default:
-		return m.cursor
+	if page := m.viewPage(); page != nil {
+		return *page*TodosPerPage + m.cursor
 	}
+	return m.cursor
 }
 
 // resetPagination resets pagination when todos are modified
 func (m *Model) resetPagination() {
-	switch m.currentView {
-	case TodayView:
-		totalPages := (len(m.todayTodos) + TodosPerPage - 1) / TodosPerPage
-		if totalPages == 0 {
-			totalPages = 1
-		}
-		if m.todayPage >= totalPages {
-			m.todayPage = totalPages - 1
-		}
-		if m.todayPage < 0 {
-			m.todayPage = 0
-		}
-	case UpcomingView:
-		totalPages := (len(m.upcomingTodos) + TodosPerPage - 1) / TodosPerPage
-		if totalPages == 0 {
-			totalPages = 1
-		}
-		if m.upcomingPage >= totalPages {
-			m.upcomingPage = totalPages - 1
-		}
-		if m.upcomingPage < 0 {
-			m.upcomingPage = 0
-		}
-	case GeneralView:
-		totalPages := (len(m.generalTodos) + TodosPerPage - 1) / TodosPerPage
+	if page := m.viewPage(); page != nil {
+		totalPages := (len(m.visibleTodos()) + TodosPerPage - 1) / TodosPerPage
 		if totalPages == 0 {
 			totalPages = 1
 		}
-		if m.generalPage >= totalPages {
-			m.generalPage = totalPages - 1
+		if *page >= totalPages {
+			*page = totalPages - 1
 		}
-		if m.generalPage < 0 {
-			m.generalPage = 0
+		if *page < 0 {
+			*page = 0
 		}
 	}
 
@@ -215,15 +320,91 @@ func (m *Model) reloadTodos() {
 	}
 
 	today := models.TodayString()
-	m.todayTodos, _ = m.repository.GetTodosForDate(today)
-	m.upcomingTodos = loadUpcomingTodos(m.repository, today)
-	m.generalTodos, _ = m.repository.GetGeneralTodos()
+	m.todayTodos = loadDayTodos(m.repository, today)
+	m.upcomingTodos = loadUpcomingTodos(m.repository, today, m.cfg.UpcomingDays)
+	m.overdueTodos = loadOverdueTodos(m.repository, today)
+	if m.currentList != "" {
+		m.generalTodos = loadListTodos(m.repository, m.currentList)
+	}
+	for i := range m.savedViews {
+		m.savedViews[i].load(m.repository)
+	}
+	m.trashTodos = loadTrashedTodos(m.repository)
+	m.lists = loadLists(m.repository)
+	m.deps = loadDependencies(m.repository)
 	m.lastRefresh = time.Now()
 }
 
+// updateTodo saves updated and shows it in place of todo. It reports false
+// if the todo could not be saved.
+func (m *Model) updateTodo(todo *models.Todo, updated models.Todo) bool {
+	if err := m.repository.UpdateTodo(&updated); err != nil {
+		m.showSaveError(err)
+		return false
+	}
+	*todo = updated
+
+	// The occurrences of a series are saved with it, so the others that are
+	// loaded were saved at the same time
+	if updated.IsOccurrence() {
+		for _, todos := range m.loadedTodos() {
+			for i := range todos {
+				if todos[i].SeriesID == updated.SeriesID {
+					todos[i].UpdatedAt = updated.UpdatedAt
+				}
+			}
+		}
+	}
+	return true
+}
+
+// showSaveError shows why a todo could not be saved. A todo that was changed
+// elsewhere is reloaded and an open edit of it is closed, so trying again
+// starts from the stored version.
+func (m *Model) showSaveError(err error) {
+	m.errorState.SetError(err)
+	if errors.Is(err, storage.ErrTodoChanged) {
+		m.inputState.ExitInputMode()
+		m.lastRefresh = time.Time{}
+		m.reloadTodos()
+	}
+}
+
+// focusTodo moves the page and cursor of the current view to the todo with
+// the given ID, if it is still listed
+func (m *Model) focusTodo(id string) {
+	page := m.viewPage()
+	if page == nil {
+		return
+	}
+
+	for i, todo := range m.visibleTodos() {
+		if todo.ID == id {
+			*page = i / TodosPerPage
+			m.cursor = i % TodosPerPage
+			return
+		}
+	}
+}
+
+// setTagFilter limits every list view to todos carrying all of tags; no
+// tags clears the filter
+func (m *Model) setTagFilter(tags []string) {
+	m.tagFilter = tags
+	m.todayPage = 0
+	m.upcomingPage = 0
+	m.overduePage = 0
+	m.generalPage = 0
+	m.trashPage = 0
+	for i := range m.savedViews {
+		m.savedViews[i].page = 0
+	}
+	m.cursor = 0
+}
+
 // Init implements tea.Model
 func (m Model) Init() tea.Cmd {
-	return nil
+	return waitForDayChange()
 }
 
 // Update implements tea.Model - handles all key presses and messages
@@ -231,6 +412,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return m.handleKeyPress(msg)
+	case dayTickMsg:
+		return m.handleDayTick()
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
@@ -245,11 +428,21 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 // Update handleKeyPress to include UpcomingView
 func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// An open dialog takes every key until it is answered
+	if len(m.dialogs) > 0 {
+		return m.handleDialogKeys(msg)
+	}
+
 	// Handle input mode first
 	if m.inputState.mode != NavigationMode {
 		return m.handleInputMode(msg)
 	}
 
+	// Typed keys go to the search; up and down still move through the matches
+	if m.searching && msg.Type != tea.KeyUp && msg.Type != tea.KeyDown {
+		return m.handleSearchKeys(msg)
+	}
+
 	// Handle QUIT keys FIRST
 	switch msg.String() {
 	case "q", "ctrl+c":
@@ -266,16 +459,52 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	}
 
+	// Visual mode takes every key but those moving through the list
+	if m.visual && !isMovementKey(msg.String()) {
+		return m.handleVisualKeys(msg)
+	}
+
+	// An open checklist takes the remaining keys
+	if m.expandedTodo() != nil {
+		return m.handleChecklistKeys(msg)
+	}
+
+	// While picking a blocker the views keep working so it can be found
+	if m.linkingID != "" {
+		switch msg.String() {
+		case "b", "enter":
+			return m.pickBlocker()
+		case "esc":
+			m.linkingID = ""
+			m.linkingTitle = ""
+			return m, nil
+		}
+	}
+
+	// Undo and redo work the same on every tab
+	switch msg.String() {
+	case "u":
+		return m.undo()
+	case "ctrl+r":
+		return m.redo()
+	}
+
 	// Handle view-specific keys (these will use hjkl)
 	switch m.currentView {
 	case TodayView:
 		return m.handleTodayViewKeys(msg)
 	case UpcomingView:
 		return m.handleUpcomingViewKeys(msg)
+	case OverdueView:
+		return m.handleOverdueViewKeys(msg)
 	case CalendarView:
 		return m.handleCalendarViewKeys(msg)
 	case GeneralView:
 		return m.handleGeneralViewKeys(msg)
+	case SavedView:
+		return m.handleSavedViewKeys(msg)
+	case TrashView:
+		return m.handleTrashViewKeys(msg)
 	}
 
 	// Handle remaining global navigation keys
@@ -317,6 +546,10 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "ctrl+s", "enter":
 		return m.handleSaveTodo()
 	case "tab":
+		if m.inputState.mode == MoveListMode {
+			m.completeListName()
+			return m, nil
+		}
 		m.inputState.SwitchField()
 		m.errorState.ClearError() // Clear errors when switching fields
 		return m, nil
@@ -333,6 +566,17 @@ func (m Model) handleInputMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleSaveTodo saves the current input as a todo
 func (m Model) handleSaveTodo() (tea.Model, tea.Cmd) {
+	switch m.inputState.mode {
+	case TagFilterMode:
+		return m.applyTagFilter()
+	case MoveListMode:
+		return m.saveMoveToList()
+	case ChecklistItemMode:
+		return m.saveChecklistItem()
+	case SavedViewMode:
+		return m.saveView()
+	}
+
 	if !m.inputState.IsValid() {
 		m.errorState.SetErrorMessage("title is required")
 		return m, nil
@@ -341,7 +585,7 @@ func (m Model) handleSaveTodo() (tea.Model, tea.Cmd) {
 	switch m.inputState.mode {
 	case AddTodoMode:
 		return m.saveNewTodo()
-	case EditTodoMode:
+	case EditTodoMode, RescheduleMode:
 		return m.saveEditedTodo()
 	}
 
@@ -361,17 +605,31 @@ func (m Model) saveNewTodo() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	var date *string
-	if m.currentView == TodayView {
-		date = &m.selectedDate
-	} else if m.currentView == CalendarView {
-		selectedDate := m.calendarState.getSelectedDate()
-		date = &selectedDate
-	} else if m.currentView == UpcomingView {
-		date = &m.selectedDate
+	date, recurrence, err := m.parseSchedule()
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	priority, err := models.ParsePriority(m.inputState.priority)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	tags, err := models.ParseTags(m.inputState.tags)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
 	}
 
 	newTodo := models.NewTodo(title, description, date)
+	newTodo.Priority = priority
+	newTodo.Tags = tags
+	if date == nil {
+		newTodo.SetList(m.inputState.list)
+	}
+	newTodo.Recurrence = recurrence
 
 	if err := m.repository.AddTodo(newTodo); err != nil {
 		m.errorState.SetError(fmt.Errorf("failed to save todo: %w", err))
@@ -386,6 +644,38 @@ func (m Model) saveNewTodo() (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// applyTagFilter filters the list views by the tags typed into the filter field
+func (m Model) applyTagFilter() (tea.Model, tea.Cmd) {
+	tags, err := models.ParseTags(m.inputState.tags)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	m.errorState.ClearError()
+	m.setTagFilter(tags)
+	m.inputState.ExitInputMode()
+	return m, nil
+}
+
+// parseSchedule resolves the date and repeat fields of the input form
+func (m Model) parseSchedule() (*string, *models.Recurrence, error) {
+	date, err := ParseDateInput(m.inputState.date)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recurrence, err := ParseRepeatInput(m.inputState.repeat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if recurrence != nil && date == nil {
+		return nil, nil, storage.ErrRecurringNeedsDate
+	}
+	return date, recurrence, nil
+}
+
 // saveEditedTodo updates an existing todo
 func (m Model) saveEditedTodo() (tea.Model, tea.Cmd) {
 	if m.inputState.editingTodo == nil {
@@ -403,12 +693,34 @@ func (m Model) saveEditedTodo() (tea.Model, tea.Cmd) {
 		return m, nil
 	}
 
-	// Update the todo
-	m.inputState.editingTodo.Title = title
-	m.inputState.editingTodo.Description = description
+	date, recurrence, err := m.parseSchedule()
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	priority, err := models.ParsePriority(m.inputState.priority)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
 
-	if err := m.repository.UpdateTodo(*m.inputState.editingTodo); err != nil {
-		m.errorState.SetError(fmt.Errorf("failed to update todo: %w", err))
+	tags, err := models.ParseTags(m.inputState.tags)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	// Update the todo, moving it to another day file if the date changed
+	todo := m.inputState.editingTodo.Clone()
+	todo.Title = title
+	todo.Description = description
+	todo.Priority = priority
+	todo.Tags = tags
+	todo.Recurrence = recurrence
+
+	if err := m.repository.MoveTodo(todo, date); err != nil {
+		m.showSaveError(fmt.Errorf("failed to update todo: %w", err))
 		return m, nil
 	}
 
@@ -422,68 +734,38 @@ func (m Model) saveEditedTodo() (tea.Model, tea.Cmd) {
 
 // View implements tea.Model - renders the current view
 func (m Model) View() string {
+	content := m.getCurrentViewContent()
+
 	// Show global errors if not in input mode
 	if m.inputState.mode == NavigationMode {
 		if errorMsg := m.errorState.GetError(); errorMsg != "" {
 			errorDisplay := errorStyle.Render("⚠ " + errorMsg)
-			return lipgloss.JoinVertical(
-				lipgloss.Left,
-				m.renderHeader(),
-				errorDisplay,
-				"",
-				m.getCurrentViewContent(),
-				m.renderFooter(),
-			)
+			if m.errorState.IsNotice() {
+				errorDisplay = successStyle.Render("✓ " + errorMsg)
+			}
+			content = lipgloss.JoinVertical(lipgloss.Left, errorDisplay, "", content)
 		}
 	}
 
-	var content string
-	switch m.currentView {
-	case TodayView:
-		content = m.renderTodayView()
-	case UpcomingView:
-		content = m.renderUpcomingView()
-	case CalendarView:
-		content = m.renderCalendarView()
-	case GeneralView:
-		content = m.renderGeneralView()
-	}
+	view := lipgloss.JoinVertical(lipgloss.Left, m.renderHeader(), content)
+	footer := m.renderFooter()
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		m.renderHeader(),
-		content,
-		m.renderFooter(),
-	)
+	// A dialog is drawn over the middle of the view it was opened from,
+	// leaving the footer with its keys visible
+	if len(m.dialogs) > 0 {
+		view = m.overlayDialog(view, m.height-lipgloss.Height(footer))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, view, footer)
 }
 
-// Update view switching
+// switchToNextView shows the tab to the right, wrapping around
 func (m Model) switchToNextView() Model {
-	switch m.currentView {
-	case TodayView:
-		m.currentView = UpcomingView
-	case UpcomingView:
-		m.currentView = CalendarView
-	case CalendarView:
-		m.currentView = GeneralView
-	case GeneralView:
-		m.currentView = TodayView
-	}
-	m.cursor = 0
-	return m
+	tabs := m.tabs()
+	return m.showTab(tabs[(m.currentTab()+1)%len(tabs)])
 }
 
+// switchToPrevView shows the tab to the left, wrapping around
 func (m Model) switchToPrevView() Model {
-	switch m.currentView {
-	case TodayView:
-		m.currentView = GeneralView
-	case UpcomingView:
-		m.currentView = TodayView
-	case CalendarView:
-		m.currentView = UpcomingView
-	case GeneralView:
-		m.currentView = CalendarView
-	}
-	m.cursor = 0
-	return m
+	tabs := m.tabs()
+	return m.showTab(tabs[(m.currentTab()+len(tabs)-1)%len(tabs)])
 }
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/config"
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// maxCarryOverShown is how many overdue todos the carry-over prompt lists
+const maxCarryOverShown = 10
+
+// dayTickMsg is sent periodically so the app notices when the day changes
+type dayTickMsg time.Time
+
+// waitForDayChange schedules the next check for a new day
+func waitForDayChange() tea.Cmd {
+	return tea.Tick(time.Minute, func(t time.Time) tea.Msg {
+		return dayTickMsg(t)
+	})
+}
+
+// handleDayTick reloads the views and applies the carry-over policy once the
+// app has been running past midnight
+func (m Model) handleDayTick() (tea.Model, tea.Cmd) {
+	today := models.TodayString()
+	if today == m.today {
+		return m, waitForDayChange()
+	}
+
+	if m.selectedDate == m.today {
+		m.selectedDate = today
+	}
+	m.today = today
+	m.lastRefresh = time.Time{}
+	m.reloadTodos()
+	m.applyCarryOver()
+	m.purgeTrash()
+	return m, waitForDayChange()
+}
+
+// applyCarryOver runs the configured carry-over policy, once a day
+func (m *Model) applyCarryOver() {
+	due, err := m.repository.CarryOverDue(m.today)
+	if err != nil {
+		m.errorState.SetError(err)
+		return
+	}
+	if !due {
+		return
+	}
+
+	switch m.cfg.CarryOver {
+	case config.CarryOverMove:
+		m.carryOver()
+	case config.CarryOverLeave:
+		if err := m.repository.MarkCarryOverChecked(m.today); err != nil {
+			m.errorState.SetError(err)
+		}
+	default:
+		overdue, err := m.repository.OverdueTodos(m.today)
+		if err != nil {
+			m.errorState.SetError(err)
+			return
+		}
+		if len(overdue) == 0 {
+			if err := m.repository.MarkCarryOverChecked(m.today); err != nil {
+				m.errorState.SetError(err)
+			}
+			return
+		}
+		m.askCarryOver(overdue)
+	}
+}
+
+// askCarryOver asks whether the unfinished todos from past days should be
+// moved to today. Saying no leaves them alone until tomorrow.
+func (m *Model) askCarryOver(overdue []models.Todo) {
+	m.openDialog(dialog{
+		kind:    confirmDialog,
+		title:   "⏰ Carry over to today?",
+		message: renderCarryOverList(overdue),
+		options: []dialogOption{{"y", "Move to today"}, {"n", "Leave them"}},
+		onAnswer: func(m Model, _ int, _ string) (tea.Model, tea.Cmd) {
+			m.carryOver()
+			return m, nil
+		},
+		onCancel: func(m Model) (tea.Model, tea.Cmd) {
+			if err := m.repository.MarkCarryOverChecked(m.today); err != nil {
+				m.errorState.SetError(err)
+			}
+			return m, nil
+		},
+	})
+}
+
+// carryOver moves the unfinished todos from past days to today
+func (m *Model) carryOver() {
+	carried, err := m.repository.CarryOver(m.today)
+	if err != nil {
+		m.errorState.SetError(err)
+		return
+	}
+
+	m.lastRefresh = time.Time{}
+	m.reloadTodos()
+	if len(carried) > 0 {
+		m.errorState.SetNotice(fmt.Sprintf("Carried %d unfinished todo(s) over to today", len(carried)))
+	}
+}
+
+// renderCarryOverList lists the unfinished todos from past days for the
+// carry-over prompt
+func renderCarryOverList(overdue []models.Todo) string {
+	items := []string{fmt.Sprintf("%d unfinished todo(s) from past days:", len(overdue))}
+
+	for i, todo := range overdue {
+		if i == maxCarryOverShown {
+			items = append(items, mutedStyle.Render(fmt.Sprintf("  … and %d more", len(overdue)-maxCarryOverShown)))
+			break
+		}
+		line := fmt.Sprintf("  %s %s%s", renderStatus(todo.CurrentStatus()), renderPriorityPrefix(todo), todo.Title)
+		items = append(items, normalItemStyle.Render(line)+mutedStyle.Render(" ("+*todo.Date+")"))
+	}
+
+	items = append(items, "", mutedStyle.Render("Set carry_over to move or leave in the config file to stop being asked."))
+	return strings.Join(items, "\n")
+}
+
+// renderCarriedFrom renders the badge of a todo carried over from a past day
+func renderCarriedFrom(todo models.Todo) string {
+	if todo.CarriedFrom == "" {
+		return ""
+	}
+	return mutedStyle.Render("↪ from " + todo.CarriedFrom)
+}
@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// expandedTodo returns the todo under the cursor if its checklist is shown
+func (m Model) expandedTodo() *models.Todo {
+	if m.expandedID == "" {
+		return nil
+	}
+	if todo := m.currentTodo(); todo != nil && todo.ID == m.expandedID {
+		return todo
+	}
+	return nil
+}
+
+// expandCurrentTodo shows the checklist of the todo under the cursor
+func (m Model) expandCurrentTodo() (tea.Model, tea.Cmd) {
+	if todo := m.currentTodo(); todo != nil {
+		m.expandedID = todo.ID
+		m.checkCursor = 0
+	}
+	return m, nil
+}
+
+// collapseTodo hides the checklist of the expanded todo
+func (m *Model) collapseTodo() {
+	m.expandedID = ""
+	m.checkCursor = 0
+}
+
+// handleChecklistKeys handles keys while the checklist of a todo is shown
+func (m Model) handleChecklistKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	todo := m.expandedTodo()
+
+	switch msg.String() {
+	case "j", "down":
+		if m.checkCursor < len(todo.Checklist)-1 {
+			m.checkCursor++
+		}
+	case "k", "up":
+		if m.checkCursor > 0 {
+			m.checkCursor--
+		}
+	case "x", " ":
+		if m.checkCursor < len(todo.Checklist) {
+			updated := todo.Clone()
+			updated.ToggleChecklistItem(m.checkCursor)
+			return m.saveChecklist(todo, updated)
+		}
+	case "a":
+		m.inputState.StartChecklistItemMode(todo, -1)
+	case "e":
+		if m.checkCursor < len(todo.Checklist) {
+			m.inputState.StartChecklistItemMode(todo, m.checkCursor)
+		}
+	case "d":
+		if m.checkCursor < len(todo.Checklist) {
+			question := fmt.Sprintf("Delete the item %q?", todo.Checklist[m.checkCursor].Title)
+			return m.confirmDeletion("☑ Delete item", question, false, func(m Model) (tea.Model, tea.Cmd) {
+				return m.deleteChecklistItem()
+			})
+		}
+	case "enter", "esc":
+		m.collapseTodo()
+	}
+	return m, nil
+}
+
+// saveChecklist stores the checklist changes in updated and shows them in
+// place of todo
+func (m Model) saveChecklist(todo *models.Todo, updated models.Todo) (tea.Model, tea.Cmd) {
+	if !m.updateTodo(todo, updated) {
+		return m, nil
+	}
+
+	m.errorState.ClearError()
+	m.refreshDependencies()
+	return m, nil
+}
+
+// saveChecklistItem adds the typed item to the expanded todo's checklist, or
+// renames the item being edited
+func (m Model) saveChecklistItem() (tea.Model, tea.Cmd) {
+	todo := m.expandedTodo()
+	if todo == nil {
+		m.errorState.SetErrorMessage("No todo being edited")
+		return m, nil
+	}
+
+	title := CleanInput(m.inputState.title)
+	if errors := ValidateTodoInput(title, ""); len(errors) > 0 {
+		m.errorState.SetErrorMessage(FormatValidationErrors(errors))
+		return m, nil
+	}
+
+	updated := todo.Clone()
+	if i := m.inputState.editingItem; i >= 0 && i < len(updated.Checklist) {
+		updated.Checklist[i].Title = title
+	} else {
+		updated.AddChecklistItem(title)
+		m.checkCursor = len(updated.Checklist) - 1
+	}
+
+	m.inputState.ExitInputMode()
+	return m.saveChecklist(todo, updated)
+}
+
+// deleteChecklistItem removes the item under the cursor from the expanded
+// todo's checklist
+func (m Model) deleteChecklistItem() (tea.Model, tea.Cmd) {
+	todo := m.expandedTodo()
+	if todo == nil || m.checkCursor >= len(todo.Checklist) {
+		return m, nil
+	}
+
+	updated := todo.Clone()
+	updated.RemoveChecklistItem(m.checkCursor)
+	if m.checkCursor >= len(updated.Checklist) && m.checkCursor > 0 {
+		m.checkCursor--
+	}
+	return m.saveChecklist(todo, updated)
+}
+
+// askCompleteChecklist asks whether completing todo should also complete
+// its open checklist items
+func (m *Model) askCompleteChecklist(todo models.Todo) {
+	done, total := todo.ChecklistProgress()
+	m.choose(
+		fmt.Sprintf("☑ Complete %q", todo.Title),
+		fmt.Sprintf("Also complete the %d open checklist item(s)?", total-done),
+		[]dialogOption{{"y", "Complete all items too"}, {"n", "Only this todo"}},
+		func(m Model, choice int) (tea.Model, tea.Cmd) {
+			current := m.currentTodo()
+			if current == nil || current.ID != todo.ID {
+				return m, nil
+			}
+
+			updated := current.Clone()
+			if choice == 0 {
+				updated.CompleteChecklist()
+			}
+			updated.SetStatus(models.StatusDone)
+			return m.saveChecklist(current, updated)
+		},
+	)
+}
+
+// renderChecklist renders the checklist of the expanded todo below its row
+func (m Model) renderChecklist(todo models.Todo) string {
+	var lines []string
+	if len(todo.Checklist) == 0 {
+		lines = append(lines, mutedStyle.Render("      No checklist items. Press 'a' to add one."))
+	}
+
+	for i, item := range todo.Checklist {
+		cursor := " "
+		checkbox := "☐"
+		style := normalItemStyle
+		if item.Done {
+			checkbox = "✓"
+			style = completedItemStyle
+		}
+		if i == m.checkCursor {
+			cursor = "›"
+			style = selectedItemStyle
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("    %s %s %s", cursor, checkbox, item.Title)))
+	}
+
+	lines = append(lines, mutedStyle.Render("      Checklist: j/k=item, x=toggle, a=add, e=edit, d=delete, enter/esc=close"))
+	return strings.Join(lines, "\n")
+}
+
+// renderChecklistProgress renders the checklist progress badge, e.g. "☑ 3/5"
+func renderChecklistProgress(todo models.Todo) string {
+	done, total := todo.ChecklistProgress()
+	if total == 0 {
+		return ""
+	}
+
+	badge := fmt.Sprintf("☑ %d/%d", done, total)
+	if done == total {
+		return successStyle.Render(badge)
+	}
+	return mutedStyle.Render(badge)
+}
@@ -23,6 +23,6 @@ func (m *Model) AddTestData() {
 	m.repository.AddTodo(general2)
 
 	// Reload data
-	m.todayTodos, _ = m.repository.GetTodosForDate(today)
-	m.generalTodos, _ = m.repository.GetGeneralTodos()
+	m.todayTodos = loadDayTodos(m.repository, today)
+	m.lists = loadLists(m.repository)
 }
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// loadDependencies loads what is needed to show which todos are blocked
+func loadDependencies(repo *storage.Repository) storage.Dependencies {
+	deps, _ := repo.LoadDependencies()
+	return deps
+}
+
+// refreshDependencies reloads the blockers after todos were completed,
+// reopened or deleted
+func (m *Model) refreshDependencies() {
+	m.deps = loadDependencies(m.repository)
+}
+
+// startPickingBlocker starts choosing a todo that the todo under the cursor
+// waits on. The blocker may be on any tab.
+func (m Model) startPickingBlocker() (tea.Model, tea.Cmd) {
+	if todo := m.currentTodo(); todo != nil {
+		m.linkingID = todo.ID
+		m.linkingTitle = todo.Title
+	}
+	return m, nil
+}
+
+// pickBlocker adds the todo under the cursor as a blocker of the todo being
+// linked, or removes it if it already was one
+func (m Model) pickBlocker() (tea.Model, tea.Cmd) {
+	blocker := m.currentTodo()
+	if blocker == nil {
+		return m, nil
+	}
+
+	todo, err := m.repository.FindTodo(m.linkingID)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	updated := todo.Clone()
+	updated.ToggleBlocker(blocker.ID)
+	return m.saveBlockers(todo, updated.BlockedBy)
+}
+
+// clearBlockersCurrentTodo removes every blocker of the todo under the cursor
+func (m Model) clearBlockersCurrentTodo() (tea.Model, tea.Cmd) {
+	if todo := m.currentTodo(); todo != nil && len(todo.BlockedBy) > 0 {
+		return m.saveBlockers(*todo, nil)
+	}
+	return m, nil
+}
+
+// saveBlockers replaces the blockers of todo and shows them in every view
+func (m Model) saveBlockers(todo models.Todo, blockers []string) (tea.Model, tea.Cmd) {
+	m.linkingID = ""
+	m.linkingTitle = ""
+
+	saved, err := m.repository.SetBlockedBy(todo, blockers)
+	if err != nil {
+		m.showSaveError(err)
+		return m, nil
+	}
+
+	// Occurrences share the blockers and save time of their series
+	for _, todos := range m.loadedTodos() {
+		for i := range todos {
+			if todos[i].ID == saved.ID || (saved.IsOccurrence() && todos[i].SeriesID == saved.SeriesID) {
+				todos[i].BlockedBy = saved.BlockedBy
+				todos[i].UpdatedAt = saved.UpdatedAt
+			}
+		}
+	}
+
+	m.errorState.ClearError()
+	m.refreshDependencies()
+	return m, nil
+}
+
+// renderBlockedBy renders the badge naming what a blocked todo waits on
+func renderBlockedBy(blockers []models.Todo) string {
+	if len(blockers) == 0 {
+		return ""
+	}
+
+	badge := "⛓ blocked by " + blockers[0].Title
+	if len(blockers) > 1 {
+		badge += fmt.Sprintf(" +%d", len(blockers)-1)
+	}
+	return blockedStyle.Render(badge)
+}
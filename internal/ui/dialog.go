@@ -0,0 +1,354 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// dialogKind is what a dialog asks for
+type dialogKind int
+
+const (
+	confirmDialog dialogKind = iota // a yes or no question
+	promptDialog                    // a single line of text
+	choiceDialog                    // one of a list of options
+)
+
+// Dialog box width, shrunk to fit narrow terminals
+const (
+	dialogWidth    = 56
+	dialogMinWidth = 24
+)
+
+// dialogOption is an answer offered by a confirm or choice dialog, picked
+// with its key or by selecting it and pressing enter
+type dialogOption struct {
+	key   string
+	label string
+}
+
+// dialog is a question shown over the current view. It takes every key
+// until it is answered or cancelled with esc.
+type dialog struct {
+	kind    dialogKind
+	title   string
+	message string
+	options []dialogOption
+	cursor  int // selected option
+
+	value string // text typed into a prompt
+	caret int    // position of the text cursor in value
+	err   string // why the typed value was rejected
+
+	// danger marks a deletion: the title is drawn in red and no is selected
+	danger bool
+
+	// validate checks the value of a prompt before it is accepted, may be nil
+	validate func(value string) error
+	onAnswer func(m Model, choice int, value string) (tea.Model, tea.Cmd)
+	onCancel func(m Model) (tea.Model, tea.Cmd) // may be nil
+}
+
+// confirm asks a yes or no question and runs onYes if the answer is yes.
+// A dangerous question starts with no selected.
+func (m *Model) confirm(title, message string, danger bool, onYes func(m Model) (tea.Model, tea.Cmd)) {
+	d := dialog{
+		kind:    confirmDialog,
+		title:   title,
+		message: message,
+		options: []dialogOption{{"y", "Yes"}, {"n", "No"}},
+		danger:  danger,
+		onAnswer: func(m Model, _ int, _ string) (tea.Model, tea.Cmd) {
+			return onYes(m)
+		},
+	}
+	if danger {
+		d.cursor = 1
+	}
+	m.openDialog(d)
+}
+
+// prompt asks for a line of text, starting from value, and passes it to
+// onSubmit once validate accepts it
+func (m *Model) prompt(title, message, value string, validate func(string) error, onSubmit func(m Model, value string) (tea.Model, tea.Cmd)) {
+	m.openDialog(dialog{
+		kind:     promptDialog,
+		title:    title,
+		message:  message,
+		value:    value,
+		caret:    len(value),
+		validate: validate,
+		onAnswer: func(m Model, _ int, value string) (tea.Model, tea.Cmd) {
+			return onSubmit(m, value)
+		},
+	})
+}
+
+// confirmDeletion runs del at once, or once confirmed when the confirm
+// setting asks about such deletions. permanent is true when what is deleted
+// is gone for good rather than moved to the trash.
+func (m Model) confirmDeletion(title, question string, permanent bool, del func(m Model) (tea.Model, tea.Cmd)) (tea.Model, tea.Cmd) {
+	if !m.cfg.Confirm.Asks(permanent) {
+		return del(m)
+	}
+	m.confirm(title, question, true, del)
+	return m, nil
+}
+
+// choose asks to pick one of options and passes its index to onChoose
+func (m *Model) choose(title, message string, options []dialogOption, onChoose func(m Model, choice int) (tea.Model, tea.Cmd)) {
+	m.openDialog(dialog{
+		kind:    choiceDialog,
+		title:   title,
+		message: message,
+		options: options,
+		onAnswer: func(m Model, choice int, _ string) (tea.Model, tea.Cmd) {
+			return onChoose(m, choice)
+		},
+	})
+}
+
+// openDialog shows d, after the dialogs already waiting for an answer
+func (m *Model) openDialog(d dialog) {
+	m.dialogs = append(m.dialogs[:len(m.dialogs):len(m.dialogs)], d)
+}
+
+// currentDialog returns the dialog being shown, or nil
+func (m *Model) currentDialog() *dialog {
+	if len(m.dialogs) == 0 {
+		return nil
+	}
+	return &m.dialogs[0]
+}
+
+// closeDialog removes the dialog being shown, so the next one, if any,
+// takes its place
+func (m *Model) closeDialog() dialog {
+	d := m.dialogs[0]
+	m.dialogs = m.dialogs[1:]
+	if len(m.dialogs) == 0 {
+		m.dialogs = nil
+	}
+	return d
+}
+
+// handleDialogKeys answers the dialog being shown
+func (m Model) handleDialogKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	d := m.currentDialog()
+	key := msg.String()
+
+	switch key {
+	case "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		return m.cancelDialog()
+	}
+
+	if d.kind == promptDialog {
+		if key == "enter" {
+			if d.validate != nil {
+				if err := d.validate(d.value); err != nil {
+					d.err = err.Error()
+					return m, nil
+				}
+			}
+			return m.answerDialog(0)
+		}
+		d.edit(key)
+		return m, nil
+	}
+
+	switch key {
+	case "enter":
+		return m.answerDialog(d.cursor)
+	case "q":
+		return m, tea.Quit
+	case "left", "right", "up", "down", "h", "l", "j", "k", "tab", "shift+tab":
+		step := 1
+		if key == "left" || key == "up" || key == "h" || key == "k" || key == "shift+tab" {
+			step = len(d.options) - 1
+		}
+		d.cursor = (d.cursor + step) % len(d.options)
+		return m, nil
+	}
+
+	for i, option := range d.options {
+		if strings.EqualFold(key, option.key) {
+			return m.answerDialog(i)
+		}
+	}
+	return m, nil
+}
+
+// answerDialog closes the dialog being shown and acts on its answer. No to
+// a yes or no question is the same as cancelling it.
+func (m Model) answerDialog(choice int) (tea.Model, tea.Cmd) {
+	if m.currentDialog().kind == confirmDialog && choice != 0 {
+		return m.cancelDialog()
+	}
+	d := m.closeDialog()
+	return d.onAnswer(m, choice, strings.TrimSpace(d.value))
+}
+
+// cancelDialog closes the dialog being shown without answering it
+func (m Model) cancelDialog() (tea.Model, tea.Cmd) {
+	d := m.closeDialog()
+	if d.onCancel != nil {
+		return d.onCancel(m)
+	}
+	return m, nil
+}
+
+// edit applies a key typed into a prompt, like HandleInput does for the
+// input forms
+func (d *dialog) edit(key string) {
+	d.err = ""
+	switch key {
+	case "backspace":
+		if d.caret > 0 {
+			d.value = d.value[:d.caret-1] + d.value[d.caret:]
+			d.caret--
+		}
+	case "delete":
+		if d.caret < len(d.value) {
+			d.value = d.value[:d.caret] + d.value[d.caret+1:]
+		}
+	case "left":
+		if d.caret > 0 {
+			d.caret--
+		}
+	case "right":
+		if d.caret < len(d.value) {
+			d.caret++
+		}
+	case "home", "ctrl+a":
+		d.caret = 0
+	case "end", "ctrl+e":
+		d.caret = len(d.value)
+	case "ctrl+u":
+		d.value = d.value[d.caret:]
+		d.caret = 0
+	default:
+		if len(key) == 1 && key >= " " && key <= "~" {
+			d.value = d.value[:d.caret] + key + d.value[d.caret:]
+			d.caret++
+		}
+	}
+}
+
+// renderDialog renders the box of the dialog being shown
+func (m Model) renderDialog() string {
+	d := m.currentDialog()
+
+	width := dialogWidth
+	if m.width > 0 && m.width-4 < width {
+		width = max(m.width-4, dialogMinWidth)
+	}
+	inner := width - 4 // border and padding
+	wrap := lipgloss.NewStyle().Width(inner)
+
+	titleStyle := warningStyle
+	border := primaryColor
+	if d.danger {
+		titleStyle = errorStyle
+		border = errorColor
+	}
+
+	lines := []string{titleStyle.Render(d.title)}
+	if d.message != "" {
+		lines = append(lines, "", wrap.Render(d.message))
+	}
+	lines = append(lines, "")
+
+	switch d.kind {
+	case confirmDialog:
+		var buttons []string
+		for i, option := range d.options {
+			label := "[" + option.key + "] " + option.label
+			if i == d.cursor {
+				buttons = append(buttons, selectedItemStyle.Render("> "+label))
+			} else {
+				buttons = append(buttons, normalItemStyle.Render("  "+label))
+			}
+		}
+		lines = append(lines, strings.Join(buttons, "   "))
+	case promptDialog:
+		value := d.value[:d.caret] + "│" + d.value[d.caret:]
+		lines = append(lines, wrap.Render(selectedItemStyle.Render(value)))
+		if d.err != "" {
+			lines = append(lines, wrap.Render(errorStyle.Render("⚠ "+d.err)))
+		}
+	case choiceDialog:
+		for i, option := range d.options {
+			label := "[" + option.key + "] " + option.label
+			if i == d.cursor {
+				lines = append(lines, selectedItemStyle.Render("> "+label))
+			} else {
+				lines = append(lines, normalItemStyle.Render("  "+label))
+			}
+		}
+	}
+
+	if len(m.dialogs) > 1 {
+		lines = append(lines, "", mutedStyle.Render(fmt.Sprintf("%d more question(s) waiting", len(m.dialogs)-1)))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(border).
+		Padding(0, 1).
+		Width(width - 2).
+		Render(strings.Join(lines, "\n"))
+}
+
+// renderDialogFooter lists the keys that answer the dialog being shown
+func (m Model) renderDialogFooter() string {
+	var help []string
+	switch m.currentDialog().kind {
+	case confirmDialog:
+		help = []string{"y: yes", "n: no", "←/→: select", "enter: answer", "esc: cancel"}
+	case promptDialog:
+		help = []string{"enter: ok", "esc: cancel"}
+	case choiceDialog:
+		help = []string{"j/k: select", "enter: pick", "esc: cancel"}
+	}
+	return footerStyle.Render(strings.Join(help, " • "))
+}
+
+// overlayDialog draws the dialog being shown over the middle of view,
+// which is padded to height lines first
+func (m Model) overlayDialog(view string, height int) string {
+	box := strings.Split(m.renderDialog(), "\n")
+	lines := strings.Split(view, "\n")
+
+	width := m.width
+	if width == 0 {
+		width = lipgloss.Width(view)
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+	height = len(lines)
+
+	boxWidth := lipgloss.Width(box[0])
+	left := max((width-boxWidth)/2, 0)
+	top := max((height-len(box))/2, 0)
+
+	for i, row := range box {
+		if top+i >= len(lines) {
+			lines = append(lines, "")
+		}
+		background := lines[top+i]
+		before := ansi.Truncate(background, left, "")
+		if gap := left - ansi.StringWidth(before); gap > 0 {
+			before += strings.Repeat(" ", gap)
+		}
+		after := ansi.TruncateLeft(background, left+boxWidth, "")
+		lines[top+i] = before + ansi.ResetStyle + row + ansi.ResetStyle + after
+	}
+	return strings.Join(lines, "\n")
+}
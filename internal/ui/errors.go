@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/WasathTheekshana/tedo/internal/storage"
 )
 
 // ErrorState manages error display
@@ -11,14 +14,25 @@ type ErrorState struct {
 	message   string
 	timestamp time.Time
 	isVisible bool
+	isNotice  bool // the message reports something that went well
 }
 
 // SetError sets an error message
 func (e *ErrorState) SetError(err error) {
+	if errors.Is(err, storage.ErrLocked) {
+		e.SetErrorMessage("Another tedo instance is saving changes - please try again")
+		return
+	}
+	if errors.Is(err, storage.ErrTodoChanged) {
+		e.SetErrorMessage("This todo was changed elsewhere - it has been reloaded, please try again")
+		return
+	}
+
 	if err != nil {
 		e.message = err.Error()
 		e.timestamp = time.Now()
 		e.isVisible = true
+		e.isNotice = false
 	}
 }
 
@@ -27,6 +41,18 @@ func (e *ErrorState) SetErrorMessage(msg string) {
 	e.message = msg
 	e.timestamp = time.Now()
 	e.isVisible = true
+	e.isNotice = false
+}
+
+// SetNotice shows an informational message in place of an error
+func (e *ErrorState) SetNotice(msg string) {
+	e.SetErrorMessage(msg)
+	e.isNotice = true
+}
+
+// IsNotice reports whether the current message is a notice, not an error
+func (e *ErrorState) IsNotice() bool {
+	return e.isNotice
 }
 
 // ClearError clears the current error
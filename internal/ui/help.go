@@ -14,11 +14,24 @@ func (m Model) GetHelpContent() string {
 - j/k: Navigate up/down in todo list
 - ←/→: Switch between tabs
 - x: Toggle todo completion
+- v: Mark several todos, on any page, to act on them together
+- Enter: Show the checklist of the selected todo
 - i: Add new todo for today
 - e: Edit selected todo
-- d: Delete selected todo
+- d: Move selected todo to the trash
+- r: Reschedule selected todo
+- p: Cycle priority of selected todo
+- s: Cycle status: open ☐, in progress ◐, waiting ◷, done ✓, cancelled ✗
+- /: Search titles and descriptions as you type (Enter keeps it)
+- #: Filter by tags
+- m: Move selected todo to another list
+- b: Pick a todo the selected todo is blocked by (b again toggles it)
+- B: Remove every blocker of the selected todo
+- esc: Clear the search and the tag filter
+- +: Save the search and tag filter as a view with its own tab
 - c: Jump to calendar view
 - Ctrl+F/B: Next/previous page (10+ todos)
+- u/Ctrl+R: Undo/redo the last change to todos, even from an earlier session
 - q: Quit application`
 
 	case UpcomingView:
@@ -26,11 +39,51 @@ func (m Model) GetHelpContent() string {
 - j/k: Navigate up/down in todo list
 - ←/→: Switch between tabs  
 - x: Toggle todo completion
+- v: Mark several todos, on any page, to act on them together
+- Enter: Show the checklist of the selected todo
 - i: Add new todo for selected date
 - e: Edit selected todo
-- d: Delete selected todo
+- d: Move selected todo to the trash
+- r: Reschedule selected todo
+- p: Cycle priority of selected todo
+- s: Cycle status: open ☐, in progress ◐, waiting ◷, done ✓, cancelled ✗
+- H: Look further ahead: next 7, 30 or 90 days, or everything planned
+- /: Search titles and descriptions as you type (Enter keeps it)
+- #: Filter by tags
+- m: Move selected todo to another list
+- b: Pick a todo the selected todo is blocked by (b again toggles it)
+- B: Remove every blocker of the selected todo
+- esc: Clear the search and the tag filter
+- +: Save the search and tag filter as a view with its own tab
 - c: Jump to calendar view
 - Ctrl+F/B: Next/previous page (10+ todos)
+- u/Ctrl+R: Undo/redo the last change to todos, even from an earlier session
+- q: Quit application`
+
+	case OverdueView:
+		return `Overdue View Help:
+- j/k: Navigate up/down in todo list
+- ←/→: Switch between tabs
+- x: Toggle todo completion
+- v: Mark several todos, on any page, to act on them together
+- t: Move selected todo to today
+- T: Move every overdue todo to today
+- Enter: Show the checklist of the selected todo
+- e: Edit selected todo
+- d: Move selected todo to the trash
+- r: Reschedule selected todo
+- p: Cycle priority of selected todo
+- s: Cycle status: open ☐, in progress ◐, waiting ◷, done ✓, cancelled ✗
+- /: Search titles and descriptions as you type (Enter keeps it)
+- #: Filter by tags
+- m: Move selected todo to another list
+- b: Pick a todo the selected todo is blocked by (b again toggles it)
+- B: Remove every blocker of the selected todo
+- esc: Clear the search and the tag filter
+- +: Save the search and tag filter as a view with its own tab
+- c: Jump to calendar view
+- Ctrl+F/B: Next/previous page (10+ todos)
+- u/Ctrl+R: Undo/redo the last change to todos, even from an earlier session
 - q: Quit application`
 
 	case CalendarView:
@@ -42,29 +95,138 @@ func (m Model) GetHelpContent() string {
 - Enter: View todos for selected date
 - i: Add todo for selected date
 - >/<: Next/previous month (alternative)
+- u/Ctrl+R: Undo/redo the last change to todos, even from an earlier session
 - q: Quit application`
 
 	case GeneralView:
-		return `General View Help:
-- j/k: Navigate up/down in todo list
+		return `Lists View Help:
+- j/k: Navigate up/down in the lists or the open list
 - ←/→: Switch between tabs
+- Enter: Open the selected list
+- n: Create a list
+- r: Rename the selected list (in the picker)
+- a: Archive or restore the selected list
+- d: Delete the selected list (only when empty)
+- A: Show or hide archived lists
+- Esc/Backspace: Back to the lists
 - x: Toggle todo completion
-- i: Add new general todo
+- v: Mark several todos, on any page, to act on them together
+- Enter: Show the checklist of the selected todo
+- i: Add new todo to the open list
 - e: Edit selected todo  
-- d: Delete selected todo
+- d: Move selected todo to the trash
+- r: Reschedule selected todo
+- p: Cycle priority of selected todo
+- s: Cycle status: open ☐, in progress ◐, waiting ◷, done ✓, cancelled ✗
+- /: Search titles and descriptions as you type (Enter keeps it)
+- #: Filter by tags
+- m: Move selected todo to another list
+- b: Pick a todo the selected todo is blocked by (b again toggles it)
+- B: Remove every blocker of the selected todo
+- esc: Clear the search and the tag filter
+- +: Save the search and tag filter as a view with its own tab
 - c: Jump to calendar view
 - Ctrl+F/B: Next/previous page (10+ todos)
+- u/Ctrl+R: Undo/redo the last change to todos, even from an earlier session
 - q: Quit application`
 
+	case SavedView:
+		return `Saved View Help:
+- j/k: Navigate up/down in the todos the view's query selects
+- ←/→: Switch between tabs
+- x: Toggle todo completion
+- v: Mark several todos, on any page, to act on them together
+- Enter: Show the checklist of the selected todo
+- e: Edit selected todo
+- d: Move selected todo to the trash
+- r: Reschedule selected todo
+- p: Cycle priority of selected todo
+- s: Cycle status: open ☐, in progress ◐, waiting ◷, done ✓, cancelled ✗
+- /: Search titles and descriptions as you type (Enter keeps it)
+- #: Filter by tags
+- m: Move selected todo to another list
+- b: Pick a todo the selected todo is blocked by (b again toggles it)
+- B: Remove every blocker of the selected todo
+- E: Edit the name and query of the view
+- [/]: Move the view's tab left/right
+- X: Remove the view
+- +: Save the search and tag filter as another view
+- esc: Clear the search and the tag filter
+- c: Jump to calendar view
+- Ctrl+F/B: Next/previous page (10+ todos)
+- u/Ctrl+R: Undo/redo the last change to todos, even from an earlier session
+- q: Quit application
+
+Views are saved in the settings file and run again whenever todos change.`
+
+	case TrashView:
+		return `Trash Help:
+- j/k: Navigate up/down in the deleted todos, most recent first
+- ←/→: Switch between tabs
+- r/Enter: Restore the selected todo to its date or list
+- d: Delete the selected todo for good
+- E: Empty the trash
+- /: Search titles and descriptions as you type (Enter keeps it)
+- #: Filter by tags
+- esc: Clear the search and the tag filter
+- c: Jump to calendar view
+- Ctrl+F/B: Next/previous page (10+ todos)
+- u/Ctrl+R: Undo/redo the last change to todos, even from an earlier session
+- q: Quit application
+
+Todos are purged after trash_days days, 30 unless set in the config file.`
+
 	default:
 		return "No help available for this view."
 	}
 }
 
+// GetChecklistHelp returns help for an expanded checklist
+func GetChecklistHelp() string {
+	return `Checklist Help:
+- j/k: Navigate up/down in the checklist
+- x/Space: Toggle the selected item
+- a: Add an item
+- e: Edit the selected item
+- d: Delete the selected item
+- Enter/Esc: Close the checklist
+
+Completing a todo with x asks whether to complete its open items too.`
+}
+
+// GetVisualHelp returns help for visual mode
+func GetVisualHelp() string {
+	return `Visual Mode Help:
+- j/k, Ctrl+F/B: Move through the todos, across pages
+- Space/v: Mark or unmark the selected todo
+- a: Mark every todo that passes the filters, or unmark them all
+- x: Complete the marked todos, or reopen them when all are done
+- d: Move the marked todos to the trash
+- r: Reschedule the marked todos to one date
+- #: Add tags to the marked todos, or remove them with -#tag
+- m: Move the marked todos to another list
+- Esc: Leave visual mode
+
+Each action saves every file it touches once and is undone with u as one.`
+}
+
+// GetCarryOverHelp returns help for the carry-over prompt
+func GetCarryOverHelp() string {
+	return `Carry-over Help:
+Unfinished todos from past days are listed when tedo starts and when the
+day changes while it is running.
+- y/Enter: Move them to today
+- n/Esc: Leave them on their dates until tomorrow
+- q: Quit application
+
+Set carry_over in the config file to move them without asking or to leave
+them alone.`
+}
+
 // GetInputHelp returns help for input mode
 func GetInputHelp() string {
 	return `Input Mode Help:
-- Tab: Switch between title and description
+- Tab: Switch between title, description, priority, tags, date and repeat
 - Enter/Ctrl+S: Save todo
 - Esc: Cancel and return to list
 - Ctrl+A: Select all text in current field
@@ -73,5 +235,10 @@ func GetInputHelp() string {
 Validation Rules:
 - Title: Required, max 100 characters
 - Description: Optional, max 500 characters
+- Priority: Optional, none, low, medium or high
+- Tags: Optional, e.g. #work #home (letters, digits, -, _ and /)
+- Date: Optional, YYYY-MM-DD or today, tomorrow, next fri, in 3 days, +2w, eom, nov 3
+- Repeat: Optional, e.g. daily, weekdays, every mon,wed, every 3 days,
+  monthly on 1, monthly on 2nd tue, with "until DATE" or "x10" to end it
 - Only printable characters allowed`
 }
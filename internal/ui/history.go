@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// undo reverts the most recent change to todos
+func (m Model) undo() (tea.Model, tea.Cmd) {
+	return m.replay(true)
+}
+
+// redo makes the most recently undone change again
+func (m Model) redo() (tea.Model, tea.Cmd) {
+	return m.replay(false)
+}
+
+// replay undoes or redoes an action, then shows the todos it changed and
+// names it
+func (m Model) replay(undo bool) (tea.Model, tea.Cmd) {
+	replay, action, done := m.repository.Redo, "redo", "Redid "
+	if undo {
+		replay, action, done = m.repository.Undo, "undo", "Undid "
+	}
+
+	entry, err := replay()
+	switch {
+	case errors.Is(err, storage.ErrNothingToUndo), errors.Is(err, storage.ErrNothingToRedo):
+		m.errorState.SetErrorMessage("Nothing to " + action)
+		return m, nil
+	case errors.Is(err, storage.ErrHistoryConflict):
+		m.errorState.SetErrorMessage("Cannot " + action + ": the todos were changed outside this session since")
+		return m, nil
+	case err != nil:
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	m.collapseTodo()
+	m.lastRefresh = time.Time{}
+	m.reloadTodos()
+	m.resetPagination()
+	if len(entry.Changes) > 0 {
+		m.focusTodo(entry.Changes[0].ID())
+	}
+	notice := done + entry.Describe()
+	if entry.Session != m.repository.Session() {
+		notice += " from an earlier session"
+	}
+	m.errorState.SetNotice(notice)
+	return m, nil
+}
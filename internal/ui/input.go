@@ -3,6 +3,7 @@ package ui
 import (
 	"strings"
 
+	"github.com/WasathTheekshana/tedo/internal/config"
 	"github.com/WasathTheekshana/tedo/internal/models"
 )
 
@@ -13,6 +14,22 @@ const (
 	NavigationMode InputMode = iota
 	AddTodoMode
 	EditTodoMode
+	RescheduleMode
+	TagFilterMode
+	MoveListMode
+	ChecklistItemMode
+	SavedViewMode
+)
+
+// Input form fields
+const (
+	titleField = iota
+	descriptionField
+	priorityField
+	tagsField
+	dateField
+	repeatField
+	fieldCount
 )
 
 // InputState holds the current input state
@@ -20,8 +37,15 @@ type InputState struct {
 	mode        InputMode
 	title       string
 	description string
+	priority    string // priority name, empty for none
+	tags        string // space or comma separated tags
+	date        string // date expression, empty for general todos
+	repeat      string // repeat rule, empty for one-off todos
+	list        string // list new undated todos are filed in, empty for the default
 	editingTodo *models.Todo
-	editField   int // 0 = title, 1 = description
+	editingItem int // checklist item being edited, -1 when adding one
+	editingView int // saved view being edited, -1 when saving a new one
+	editField   int // one of the input form fields, titleField to repeatField
 	cursor      int // cursor position in input field
 }
 
@@ -36,12 +60,17 @@ func NewInputState() InputState {
 	}
 }
 
-// StartAddMode starts adding a new todo
-func (s *InputState) StartAddMode() {
+// StartAddMode starts adding a new todo, prefilling the date field
+func (s *InputState) StartAddMode(date string) {
 	s.mode = AddTodoMode
 	s.title = ""
 	s.description = ""
-	s.editField = 0
+	s.priority = ""
+	s.tags = ""
+	s.date = date
+	s.repeat = ""
+	s.list = ""
+	s.editField = titleField
 	s.cursor = 0
 }
 
@@ -51,17 +80,91 @@ func (s *InputState) StartEditMode(todo *models.Todo) {
 	s.editingTodo = todo
 	s.title = todo.Title
 	s.description = todo.Description
-	s.editField = 0
+	s.priority = ""
+	if todo.Priority != models.PriorityNone {
+		s.priority = todo.Priority.String()
+	}
+	s.tags = models.FormatTags(todo.Tags)
+	s.date = ""
+	if todo.Date != nil {
+		s.date = *todo.Date
+	}
+	s.repeat = ""
+	if todo.Recurrence != nil {
+		s.repeat = todo.Recurrence.String()
+	}
+	s.editField = titleField
 	s.cursor = len(s.title)
 }
 
+// StartRescheduleMode starts changing only the date of an existing todo
+func (s *InputState) StartRescheduleMode(todo *models.Todo) {
+	s.StartEditMode(todo)
+	s.mode = RescheduleMode
+	s.editField = dateField
+	s.cursor = len(s.date)
+}
+
+// StartTagFilterMode starts editing the tag filter of the list views
+func (s *InputState) StartTagFilterMode(tags []string) {
+	s.ExitInputMode()
+	s.mode = TagFilterMode
+	s.tags = models.FormatTags(tags)
+	s.editField = tagsField
+	s.cursor = len(s.tags)
+}
+
+// StartMoveListMode starts choosing the list to move todo to. The list is
+// typed in the title field.
+func (s *InputState) StartMoveListMode(todo *models.Todo) {
+	s.ExitInputMode()
+	s.mode = MoveListMode
+	s.editingTodo = todo
+}
+
+// StartChecklistItemMode starts adding an item to the checklist of todo, or
+// editing item i if it is not -1. The item is edited in the title field.
+func (s *InputState) StartChecklistItemMode(todo *models.Todo, i int) {
+	s.ExitInputMode()
+	s.mode = ChecklistItemMode
+	s.editingTodo = todo
+	s.editingItem = i
+	if i >= 0 && i < len(todo.Checklist) {
+		s.title = todo.Checklist[i].Title
+	}
+	s.cursor = len(s.title)
+}
+
+// StartSavedViewMode starts saving a new view, or editing saved view i if it
+// is not -1. The name is edited in the title field and the query in the
+// description field.
+func (s *InputState) StartSavedViewMode(i int, view config.View) {
+	s.ExitInputMode()
+	s.mode = SavedViewMode
+	s.editingView = i
+	s.title = view.Name
+	s.description = view.Query
+	if i < 0 {
+		// New views are named last, once the query shows what they hold
+		s.editField = descriptionField
+	}
+	s.cursor = len(*s.getCurrentField())
+}
+
 // ExitInputMode exits any input mode
 func (s *InputState) ExitInputMode() {
 	s.mode = NavigationMode
 	s.title = ""
 	s.description = ""
+	s.priority = ""
+	s.tags = ""
+	s.date = ""
+	s.repeat = ""
+	s.list = ""
 	s.editingTodo = nil
-	s.editField = 0
+	s.editingItem = -1
+	s.editingView = -1
+	s.editField = titleField
 	s.cursor = 0
 }
 
@@ -100,23 +203,38 @@ func (s *InputState) HandleInput(key string) {
 	}
 }
 
-// SwitchField switches between title and description fields
+// SwitchField cycles through the title, description, priority, tags, date and
+// repeat fields. A saved view has a name and a query, and the other modes
+// edit a single field, so the field never changes there.
 func (s *InputState) SwitchField() {
-	if s.editField == 0 {
-		s.editField = 1
-		s.cursor = len(s.description)
-	} else {
-		s.editField = 0
-		s.cursor = len(s.title)
+	if s.mode == SavedViewMode {
+		s.editField = (s.editField + 1) % priorityField
+		s.cursor = len(*s.getCurrentField())
+		return
 	}
+	if s.mode != AddTodoMode && s.mode != EditTodoMode {
+		return
+	}
+	s.editField = (s.editField + 1) % fieldCount
+	s.cursor = len(*s.getCurrentField())
 }
 
 // getCurrentField returns pointer to the currently edited field
 func (s *InputState) getCurrentField() *string {
-	if s.editField == 0 {
+	switch s.editField {
+	case descriptionField:
+		return &s.description
+	case priorityField:
+		return &s.priority
+	case tagsField:
+		return &s.tags
+	case dateField:
+		return &s.date
+	case repeatField:
+		return &s.repeat
+	default:
 		return &s.title
 	}
-	return &s.description
 }
 
 // IsValid returns true if the input is valid for saving
@@ -1,6 +1,14 @@
 package ui
 
-import tea "github.com/charmbracelet/bubbletea"
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/config"
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
 
 // handleTodayViewKeys handles keys specific to today view
 func (m Model) handleTodayViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -37,13 +45,39 @@ func (m Model) handleTodayViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 	case "x":
 		return m.toggleCurrentTodo(), nil
+	case "v":
+		return m.startVisual()
+	case "enter":
+		return m.expandCurrentTodo()
 	case "i":
-		m.inputState.StartAddMode()
+		m.inputState.StartAddMode(m.selectedDate)
 		return m, nil
 	case "e":
 		return m.editCurrentTodo()
 	case "d":
 		return m.deleteCurrentTodo()
+	case "r":
+		return m.rescheduleCurrentTodo()
+	case "p":
+		return m.cyclePriorityCurrentTodo()
+	case "s":
+		return m.cycleStatusCurrentTodo()
+	case "#":
+		m.inputState.StartTagFilterMode(m.tagFilter)
+		return m, nil
+	case "m":
+		return m.moveCurrentTodo()
+	case "b":
+		return m.startPickingBlocker()
+	case "B":
+		return m.clearBlockersCurrentTodo()
+	case "/":
+		return m.startSearch()
+	case "+":
+		return m.startSavingView()
+	case "esc":
+		m.clearFilters()
+		return m, nil
 	case "c":
 		// Press 'c' to go to calendar
 		m.currentView = CalendarView
@@ -80,7 +114,7 @@ func (m Model) handleCalendarViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "enter":
 		// Switch to today view with selected date
 		m.selectedDate = m.calendarState.getSelectedDate()
-		m.todayTodos, _ = m.repository.GetTodosForDate(m.selectedDate)
+		m.todayTodos = loadDayTodos(m.repository, m.selectedDate)
 		m.currentView = TodayView
 		m.cursor = 0
 		m.todayPage = 0
@@ -88,7 +122,7 @@ func (m Model) handleCalendarViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case "i":
 		// Add todo for selected date
 		m.selectedDate = m.calendarState.getSelectedDate()
-		m.inputState.StartAddMode()
+		m.inputState.StartAddMode(m.selectedDate)
 		return m, nil
 	}
 	return m, nil
@@ -96,6 +130,10 @@ func (m Model) handleCalendarViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleGeneralViewKeys handles keys specific to general view
 func (m Model) handleGeneralViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.currentList == "" {
+		return m.handleListPickerKeys(msg)
+	}
+
 	// Remove h/l for tab switching - now only hjkl for navigation
 	paginatedTodos, currentPage, totalPages := m.getPaginatedTodos()
 
@@ -128,14 +166,49 @@ func (m Model) handleGeneralViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cursor = 0
 		}
 	case "x":
-		return m.toggleCurrentGeneralTodo(), nil
+		return m.toggleCurrentTodo(), nil
+	case "v":
+		return m.startVisual()
+	case "enter":
+		return m.expandCurrentTodo()
 	case "i":
-		m.inputState.StartAddMode()
+		m.inputState.StartAddMode("")
+		m.inputState.list = m.currentList
 		return m, nil
 	case "e":
-		return m.editCurrentGeneralTodo()
+		return m.editCurrentTodo()
 	case "d":
-		return m.deleteCurrentGeneralTodo()
+		return m.deleteCurrentTodo()
+	case "r":
+		return m.rescheduleCurrentTodo()
+	case "p":
+		return m.cyclePriorityCurrentTodo()
+	case "s":
+		return m.cycleStatusCurrentTodo()
+	case "#":
+		m.inputState.StartTagFilterMode(m.tagFilter)
+		return m, nil
+	case "m":
+		return m.moveCurrentTodo()
+	case "b":
+		return m.startPickingBlocker()
+	case "B":
+		return m.clearBlockersCurrentTodo()
+	case "/":
+		return m.startSearch()
+	case "+":
+		return m.startSavingView()
+	case "esc":
+		// Clear the filters first, then go back to the list picker
+		if m.isFiltered() {
+			m.clearFilters()
+		} else {
+			m.closeList()
+		}
+		return m, nil
+	case "backspace":
+		m.closeList()
+		return m, nil
 	case "c":
 		// Press 'c' to go to calendar
 		m.currentView = CalendarView
@@ -144,97 +217,63 @@ func (m Model) handleGeneralViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// toggleCurrentTodo toggles completion of current today todo
-func (m Model) toggleCurrentTodo() Model {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.todayTodos) {
-			m.todayTodos[absoluteIndex].Toggle()
-			if err := m.repository.UpdateTodo(m.todayTodos[absoluteIndex]); err != nil {
-				m.errorState.SetError(err)
-			}
-		}
-	}
-	return m
-}
+// currentTodo returns the todo under the cursor in the current list view
+func (m Model) currentTodo() *models.Todo {
+	todos := m.listTodos()
+	indices := m.visibleIndices()
 
-// toggleCurrentGeneralTodo toggles completion of current general todo
-func (m Model) toggleCurrentGeneralTodo() Model {
 	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.generalTodos) {
-			m.generalTodos[absoluteIndex].Toggle()
-			if err := m.repository.UpdateTodo(m.generalTodos[absoluteIndex]); err != nil {
-				m.errorState.SetError(err)
-			}
-		}
+	absoluteIndex := m.getAbsoluteCursor()
+	if m.cursor >= len(paginatedTodos) || absoluteIndex >= len(indices) {
+		return nil
 	}
-	return m
+	return &todos[indices[absoluteIndex]]
 }
 
-// editCurrentTodo starts editing the current today todo
-func (m Model) editCurrentTodo() (tea.Model, tea.Cmd) {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.todayTodos) {
-			m.inputState.StartEditMode(&m.todayTodos[absoluteIndex])
-		}
+// rescheduleCurrentTodo opens the date field for the todo under the cursor
+func (m Model) rescheduleCurrentTodo() (tea.Model, tea.Cmd) {
+	if todo := m.currentTodo(); todo != nil {
+		m.inputState.StartRescheduleMode(todo)
 	}
 	return m, nil
 }
 
-// editCurrentGeneralTodo starts editing the current general todo
-func (m Model) editCurrentGeneralTodo() (tea.Model, tea.Cmd) {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.generalTodos) {
-			m.inputState.StartEditMode(&m.generalTodos[absoluteIndex])
-		}
+// cyclePriorityCurrentTodo raises the priority of the todo under the cursor,
+// wrapping from high back to none, and keeps the cursor on it after re-sorting
+func (m Model) cyclePriorityCurrentTodo() (tea.Model, tea.Cmd) {
+	todo := m.currentTodo()
+	if todo == nil {
+		return m, nil
 	}
-	return m, nil
-}
 
-// deleteCurrentTodo deletes the current today todo
-func (m Model) deleteCurrentTodo() (tea.Model, tea.Cmd) {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.todayTodos) {
-			todo := m.todayTodos[absoluteIndex]
-			if err := m.repository.DeleteTodo(todo.ID, todo.Date); err != nil {
-				m.errorState.SetError(err)
-				return m, nil
-			}
+	updated := todo.Clone()
+	updated.Priority = updated.Priority.Next()
+	if !m.updateTodo(todo, updated) {
+		return m, nil
+	}
 
-			// Reload todos and reset pagination
-			m.todayTodos, _ = m.repository.GetTodosForDate(m.selectedDate)
-			m.resetPagination()
-		}
+	for _, todos := range m.loadedTodos() {
+		models.SortTodos(todos)
 	}
+	m.focusTodo(updated.ID)
 	return m, nil
 }
 
-// deleteCurrentGeneralTodo deletes the current general todo
-func (m Model) deleteCurrentGeneralTodo() (tea.Model, tea.Cmd) {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.generalTodos) {
-			todo := m.generalTodos[absoluteIndex]
-			if err := m.repository.DeleteTodo(todo.ID, nil); err != nil {
-				m.errorState.SetError(err)
-				return m, nil
-			}
+// cycleStatusCurrentTodo moves the todo under the cursor to its next status:
+// open, in progress, waiting, done, cancelled and back to open
+func (m Model) cycleStatusCurrentTodo() (tea.Model, tea.Cmd) {
+	todo := m.currentTodo()
+	if todo == nil {
+		return m, nil
+	}
 
-			// Reload todos and reset pagination
-			m.generalTodos, _ = m.repository.GetGeneralTodos()
-			m.resetPagination()
-		}
+	updated := todo.Clone()
+	updated.SetStatus(updated.CurrentStatus().Next())
+	if !m.updateTodo(todo, updated) {
+		return m, nil
 	}
+
+	m.refreshDependencies()
 	return m, nil
 }
 
@@ -270,14 +309,42 @@ func (m Model) handleUpcomingViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.cursor = 0
 		}
 	case "x":
-		return m.toggleCurrentUpcomingTodo(), nil
+		return m.toggleCurrentTodo(), nil
+	case "v":
+		return m.startVisual()
+	case "enter":
+		return m.expandCurrentTodo()
 	case "i":
-		m.inputState.StartAddMode()
+		m.inputState.StartAddMode(models.FormatDate(time.Now().AddDate(0, 0, 1)))
 		return m, nil
 	case "e":
-		return m.editCurrentUpcomingTodo()
+		return m.editCurrentTodo()
 	case "d":
-		return m.deleteCurrentUpcomingTodo()
+		return m.deleteCurrentTodo()
+	case "r":
+		return m.rescheduleCurrentTodo()
+	case "p":
+		return m.cyclePriorityCurrentTodo()
+	case "s":
+		return m.cycleStatusCurrentTodo()
+	case "H":
+		return m.cycleUpcomingHorizon()
+	case "#":
+		m.inputState.StartTagFilterMode(m.tagFilter)
+		return m, nil
+	case "m":
+		return m.moveCurrentTodo()
+	case "b":
+		return m.startPickingBlocker()
+	case "B":
+		return m.clearBlockersCurrentTodo()
+	case "/":
+		return m.startSearch()
+	case "+":
+		return m.startSavingView()
+	case "esc":
+		m.clearFilters()
+		return m, nil
 	case "c":
 		m.currentView = CalendarView
 		return m, nil
@@ -285,46 +352,116 @@ func (m Model) handleUpcomingViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-// Add these functions to handle upcoming todos
-func (m Model) toggleCurrentUpcomingTodo() Model {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.upcomingTodos) {
-			m.upcomingTodos[absoluteIndex].Toggle()
-			if err := m.repository.UpdateTodo(m.upcomingTodos[absoluteIndex]); err != nil {
-				m.errorState.SetError(err)
-			}
+// cycleUpcomingHorizon widens how far ahead the Upcoming tab looks, wrapping
+// from unlimited back to a week, and keeps the choice in the config file
+func (m Model) cycleUpcomingHorizon() (tea.Model, tea.Cmd) {
+	m.cfg.UpcomingDays = config.NextUpcomingHorizon(m.cfg.UpcomingDays)
+	m.upcomingTodos = loadUpcomingTodos(m.repository, m.today, m.cfg.UpcomingDays)
+	m.upcomingPage = 0
+	m.cursor = 0
+	m.collapseTodo()
+
+	days := m.cfg.UpcomingDays
+	err := m.cfg.Update(func(cfg *config.Config) {
+		cfg.UpcomingDays = days
+	})
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+	m.errorState.ClearError()
+	return m, nil
+}
+
+// toggleCurrentTodo toggles completion of the todo under the cursor. A todo
+// with open checklist items first asks whether to complete them too.
+func (m Model) toggleCurrentTodo() Model {
+	if todo := m.currentTodo(); todo != nil {
+		if !todo.Completed && todo.HasOpenChecklistItems() {
+			m.askCompleteChecklist(*todo)
+			return m
 		}
+		updated := todo.Clone()
+		updated.Toggle()
+		m.updateTodo(todo, updated)
+		m.refreshDependencies()
 	}
 	return m
 }
 
-func (m Model) editCurrentUpcomingTodo() (tea.Model, tea.Cmd) {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.upcomingTodos) {
-			m.inputState.StartEditMode(&m.upcomingTodos[absoluteIndex])
-		}
+// editCurrentTodo starts editing the todo under the cursor
+func (m Model) editCurrentTodo() (tea.Model, tea.Cmd) {
+	if todo := m.currentTodo(); todo != nil {
+		m.inputState.StartEditMode(todo)
 	}
 	return m, nil
 }
 
-func (m Model) deleteCurrentUpcomingTodo() (tea.Model, tea.Cmd) {
-	paginatedTodos, _, _ := m.getPaginatedTodos()
-	if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos) {
-		absoluteIndex := m.getAbsoluteCursor()
-		if absoluteIndex < len(m.upcomingTodos) {
-			todo := m.upcomingTodos[absoluteIndex]
-			if err := m.repository.DeleteTodo(todo.ID, todo.Date); err != nil {
-				m.errorState.SetError(err)
-				return m, nil
+// deleteCurrentTodo moves the todo under the cursor to the trash. For an
+// occurrence of a recurring todo it asks whether to skip just that date or
+// to trash the whole series.
+func (m Model) deleteCurrentTodo() (tea.Model, tea.Cmd) {
+	todo := m.currentTodo()
+	if todo == nil {
+		return m, nil
+	}
+
+	deleted := *todo
+	if deleted.IsOccurrence() {
+		message := fmt.Sprintf("%q repeats. Delete only the occurrence on %s, or move the whole series to the trash?", deleted.Title, *deleted.Date)
+		options := []dialogOption{{"o", "Only this occurrence"}, {"a", "All occurrences"}}
+		m.choose("Delete recurring todo", message, options, func(m Model, choice int) (tea.Model, tea.Cmd) {
+			if choice == 1 {
+				return m.deleteTodo(seriesOf(deleted))
 			}
+			return m.deleteTodo(deleted)
+		})
+		return m, nil
+	}
 
-			m.reloadTodos()
-			m.resetPagination()
-		}
+	question := fmt.Sprintf("Move %q to the trash?", deleted.Title)
+	if deleted.IsRecurring() {
+		question = fmt.Sprintf("Move %q and all its occurrences to the trash?", deleted.Title)
+	}
+	return m.confirmDeletion("Delete todo", question, false, func(m Model) (tea.Model, tea.Cmd) {
+		return m.deleteTodo(deleted)
+	})
+}
+
+// seriesOf returns the recurring series an occurrence belongs to, as far as
+// the occurrence tells
+func seriesOf(occurrence models.Todo) models.Todo {
+	series := occurrence.Clone()
+	series.ID = occurrence.SeriesID
+	series.SeriesID = ""
+	return series
+}
+
+// deleteTodo moves todo to the trash, or skips it when it is an occurrence
+// of a recurring todo
+func (m Model) deleteTodo(todo models.Todo) (tea.Model, tea.Cmd) {
+	if err := m.repository.DeleteTodo(todo); err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	// Reload todos and reset pagination
+	switch m.currentView {
+	case TodayView:
+		m.todayTodos = loadDayTodos(m.repository, m.selectedDate)
+	case GeneralView:
+		m.generalTodos = loadListTodos(m.repository, m.currentList)
+		m.refreshLists()
+	default:
+		m.reloadTodos()
+	}
+	m.trashTodos = loadTrashedTodos(m.repository)
+	m.refreshDependencies()
+	m.resetPagination()
+	if todo.IsOccurrence() {
+		m.errorState.SetNotice(fmt.Sprintf("Skipped %q on %s", todo.Title, *todo.Date))
+	} else {
+		m.errorState.SetNotice(fmt.Sprintf("Moved %q to the trash", todo.Title))
 	}
 	return m, nil
 }
@@ -0,0 +1,309 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// listEntry is a list shown in the list picker along with its todo counts
+type listEntry struct {
+	list  models.List
+	open  int
+	total int
+}
+
+// loadLists loads every list with its todo counts
+func loadLists(repo *storage.Repository) []listEntry {
+	lists, _ := repo.GetLists()
+
+	entries := make([]listEntry, 0, len(lists))
+	for _, list := range lists {
+		entry := listEntry{list: list}
+		todos, _ := repo.GetListTodos(list.ID)
+		for _, todo := range todos {
+			entry.total++
+			if !todo.Completed {
+				entry.open++
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// loadListTodos loads the todos of a list in display order
+func loadListTodos(repo *storage.Repository, id string) []models.Todo {
+	todos, _ := repo.GetListTodos(id)
+	models.SortTodos(todos)
+	return todos
+}
+
+// pickerLists returns the lists shown in the picker, hiding archived ones
+// unless they were asked for
+func (m Model) pickerLists() []listEntry {
+	var entries []listEntry
+	for _, entry := range m.lists {
+		if m.showArchived || !entry.list.Archived {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// currentPickerList returns the list under the cursor in the picker
+func (m Model) currentPickerList() *models.List {
+	entries := m.pickerLists()
+	if m.listCursor < 0 || m.listCursor >= len(entries) {
+		return nil
+	}
+	return &entries[m.listCursor].list
+}
+
+// listName returns the display name of the list with the given ID
+func (m Model) listName(id string) string {
+	if id == "" {
+		id = models.DefaultListID
+	}
+	for _, entry := range m.lists {
+		if entry.list.ID == id {
+			return entry.list.Name
+		}
+	}
+	return id
+}
+
+// openList shows the todos of a list on the General tab
+func (m *Model) openList(id string) {
+	m.currentList = id
+	m.generalTodos = loadListTodos(m.repository, id)
+	m.generalPage = 0
+	m.cursor = 0
+}
+
+// closeList returns the General tab to the list picker
+func (m *Model) closeList() {
+	for i, entry := range m.pickerLists() {
+		if entry.list.ID == m.currentList {
+			m.listCursor = i
+		}
+	}
+	m.currentList = ""
+	m.cursor = 0
+}
+
+// refreshLists reloads the lists and keeps the picker cursor in range
+func (m *Model) refreshLists() {
+	m.lists = loadLists(m.repository)
+	if n := len(m.pickerLists()); m.listCursor >= n {
+		m.listCursor = n - 1
+	}
+	if m.listCursor < 0 {
+		m.listCursor = 0
+	}
+}
+
+// handleListPickerKeys handles keys on the General tab while picking a list
+func (m Model) handleListPickerKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	entries := m.pickerLists()
+
+	switch msg.String() {
+	case "j", "down":
+		if m.listCursor < len(entries)-1 {
+			m.listCursor++
+		}
+	case "k", "up":
+		if m.listCursor > 0 {
+			m.listCursor--
+		}
+	case "enter", "l":
+		if list := m.currentPickerList(); list != nil {
+			m.openList(list.ID)
+		}
+	case "n":
+		m.promptListName(nil)
+	case "r":
+		if list := m.currentPickerList(); list != nil {
+			m.promptListName(list)
+		}
+	case "a":
+		if list := m.currentPickerList(); list != nil {
+			if err := m.repository.SetListArchived(list.ID, !list.Archived); err != nil {
+				m.errorState.SetError(err)
+				return m, nil
+			}
+			m.errorState.ClearError()
+			m.refreshLists()
+		}
+	case "A":
+		m.showArchived = !m.showArchived
+		m.refreshLists()
+	case "d":
+		if list := m.currentPickerList(); list != nil {
+			id := list.ID
+			question := fmt.Sprintf("Delete the list %q?", list.Name)
+			return m.confirmDeletion("📝 Delete list", question, true, func(m Model) (tea.Model, tea.Cmd) {
+				return m.deleteList(id)
+			})
+		}
+	case "c":
+		m.currentView = CalendarView
+	}
+	return m, nil
+}
+
+// deleteList deletes the empty list with the given ID
+func (m Model) deleteList(id string) (tea.Model, tea.Cmd) {
+	if err := m.repository.DeleteList(id); err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+	m.errorState.ClearError()
+	m.refreshLists()
+	return m, nil
+}
+
+// promptListName asks for the name of a new list, or for a new name of
+// list if it is not nil
+func (m *Model) promptListName(list *models.List) {
+	title, id, name := "📝 New List", "", ""
+	if list != nil {
+		title, id, name = "📝 Rename List", list.ID, list.Name
+	}
+
+	m.prompt(title, "Name:", name, validateListName, func(m Model, name string) (tea.Model, tea.Cmd) {
+		return m.saveListName(id, name)
+	})
+}
+
+// validateListName rejects list names that are empty once cleaned
+func validateListName(name string) error {
+	if CleanInput(name) == "" {
+		return errors.New("list name is required")
+	}
+	return nil
+}
+
+// saveListName creates a list named name, or renames the list with the
+// given ID if it is not empty
+func (m Model) saveListName(id, name string) (tea.Model, tea.Cmd) {
+	name = CleanInput(name)
+
+	var err error
+	if id != "" {
+		err = m.repository.RenameList(id, name)
+	} else {
+		_, err = m.repository.CreateList(name)
+	}
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	m.errorState.ClearError()
+	m.refreshLists()
+	return m, nil
+}
+
+// moveCurrentTodo asks which list to move the todo under the cursor to
+func (m Model) moveCurrentTodo() (tea.Model, tea.Cmd) {
+	if todo := m.currentTodo(); todo != nil {
+		m.inputState.StartMoveListMode(todo)
+	}
+	return m, nil
+}
+
+// saveMoveToList files the todo being moved in the list typed by the user
+func (m Model) saveMoveToList() (tea.Model, tea.Cmd) {
+	todo := m.inputState.editingTodo
+	if todo == nil {
+		m.errorState.SetErrorMessage("No todo being moved")
+		return m, nil
+	}
+
+	list, err := m.repository.FindList(m.inputState.title)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	if err := m.repository.MoveTodoToList(*todo, list.ID); err != nil {
+		m.showSaveError(fmt.Errorf("failed to move todo: %w", err))
+		return m, nil
+	}
+
+	m.errorState.ClearError()
+	m.reloadTodos()
+	if m.currentList != "" {
+		m.generalTodos = loadListTodos(m.repository, m.currentList)
+	}
+	m.refreshLists()
+	m.resetPagination()
+	m.inputState.ExitInputMode()
+	return m, nil
+}
+
+// completeListName replaces the list typed while moving a todo with the
+// next list that todos can be filed in
+func (m *Model) completeListName() {
+	var names []string
+	for _, entry := range m.lists {
+		if !entry.list.Archived {
+			names = append(names, entry.list.Name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	next := 0
+	for i, name := range names {
+		if strings.EqualFold(name, strings.TrimSpace(m.inputState.title)) {
+			next = (i + 1) % len(names)
+		}
+	}
+	m.inputState.title = names[next]
+	m.inputState.cursor = len(names[next])
+}
+
+// renderListPicker renders the lists on the General tab
+func (m Model) renderListPicker() string {
+	entries := m.pickerLists()
+
+	header := fmt.Sprintf("📝 Lists (%d)", len(entries))
+	if m.showArchived {
+		header += " - including archived"
+	}
+	items := []string{header + "\n"}
+
+	if len(entries) == 0 {
+		items = append(items, "No lists!", "", "Press 'n' to create a list.")
+	}
+
+	for i, entry := range entries {
+		cursor := " "
+		style := normalItemStyle
+		if i == m.listCursor {
+			cursor = ">"
+			style = selectedItemStyle
+		}
+		if entry.list.Archived && i != m.listCursor {
+			style = mutedStyle
+		}
+
+		line := fmt.Sprintf("%s %s", cursor, entry.list.Name)
+		counts := fmt.Sprintf(" (%d open, %d total)", entry.open, entry.total)
+		if entry.list.Archived {
+			counts += " [archived]"
+		}
+		items = append(items, style.Render(line)+mutedStyle.Render(counts))
+	}
+
+	items = append(items, "", mutedStyle.Render("Lists: enter=open, n=new, r=rename, a=archive, d=delete, A=show archived"))
+	return baseStyle.Render(strings.Join(items, "\n"))
+}
@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// loadOverdueTodos loads the unfinished todos of every day before today,
+// oldest day first
+func loadOverdueTodos(repo *storage.Repository, today string) []models.Todo {
+	todos, _ := repo.OverdueTodos(today)
+	return todos
+}
+
+// overdueCount returns how many overdue todos are still open. Todos completed
+// on the Overdue tab stay listed until the next reload.
+func (m Model) overdueCount() int {
+	count := 0
+	for _, todo := range m.overdueTodos {
+		if !todo.Completed {
+			count++
+		}
+	}
+	return count
+}
+
+// handleOverdueViewKeys handles keys specific to overdue view
+func (m Model) handleOverdueViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	paginatedTodos, currentPage, totalPages := m.getPaginatedTodos()
+
+	switch msg.String() {
+	case "j", "down":
+		if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos)-1 {
+			m.cursor++
+		} else if len(paginatedTodos) > 0 && m.cursor == len(paginatedTodos)-1 && currentPage < totalPages-1 {
+			m.overduePage++
+			m.cursor = 0
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		} else if m.cursor == 0 && currentPage > 0 {
+			m.overduePage--
+			newPaginatedTodos, _, _ := m.getPaginatedTodos()
+			m.cursor = len(newPaginatedTodos) - 1
+		}
+	case "ctrl+f", "page_down":
+		if currentPage < totalPages-1 {
+			m.overduePage++
+			m.cursor = 0
+		}
+	case "ctrl+b", "page_up":
+		if currentPage > 0 {
+			m.overduePage--
+			m.cursor = 0
+		}
+	case "x":
+		return m.toggleCurrentTodo(), nil
+	case "v":
+		return m.startVisual()
+	case "enter":
+		return m.expandCurrentTodo()
+	case "e":
+		return m.editCurrentTodo()
+	case "d":
+		return m.deleteCurrentTodo()
+	case "r":
+		return m.rescheduleCurrentTodo()
+	case "t":
+		return m.moveToTodayCurrentTodo()
+	case "T":
+		m.carryOver()
+		m.resetPagination()
+		return m, nil
+	case "p":
+		return m.cyclePriorityCurrentTodo()
+	case "s":
+		return m.cycleStatusCurrentTodo()
+	case "#":
+		m.inputState.StartTagFilterMode(m.tagFilter)
+		return m, nil
+	case "m":
+		return m.moveCurrentTodo()
+	case "b":
+		return m.startPickingBlocker()
+	case "B":
+		return m.clearBlockersCurrentTodo()
+	case "/":
+		return m.startSearch()
+	case "+":
+		return m.startSavingView()
+	case "esc":
+		m.clearFilters()
+		return m, nil
+	case "c":
+		m.currentView = CalendarView
+		return m, nil
+	}
+	return m, nil
+}
+
+// moveToTodayCurrentTodo carries the overdue todo under the cursor over to
+// today
+func (m Model) moveToTodayCurrentTodo() (tea.Model, tea.Cmd) {
+	todo := m.currentTodo()
+	if todo == nil {
+		return m, nil
+	}
+
+	moved, err := m.repository.CarryOverTodo(*todo, m.today)
+	if err != nil {
+		m.showSaveError(err)
+		return m, nil
+	}
+
+	m.errorState.SetNotice(fmt.Sprintf("Moved %q to today", moved.Title))
+	m.lastRefresh = time.Time{}
+	m.reloadTodos()
+	m.resetPagination()
+	return m, nil
+}
+
+// renderOverdueView renders the unfinished todos of past days, grouped by day
+func (m Model) renderOverdueView() string {
+	// If in input mode, show the input form
+	if m.inputState.mode != NavigationMode {
+		return m.renderInputForm()
+	}
+
+	return m.renderTodoList(
+		"⏰ Overdue Todos",
+		"Nothing overdue!\n\nUnfinished todos from past days show up here.",
+		dateGrouped,
+	)
+}
+
+// renderDayHeading renders the heading above the todos of a past day, e.g.
+// "Thu 2026-10-15 · 3 days ago"
+func (m Model) renderDayHeading(date string) string {
+	heading := date
+	if day, err := time.Parse("2006-01-02", date); err == nil {
+		heading = day.Format("Mon") + " " + date
+		if today, err := time.Parse("2006-01-02", m.today); err == nil {
+			switch days := int(today.Sub(day).Hours() / 24); days {
+			case 1:
+				heading += " · yesterday"
+			default:
+				heading += fmt.Sprintf(" · %d days ago", days)
+			}
+		}
+	}
+	return warningStyle.Render(heading)
+}
@@ -18,7 +18,7 @@ func (m Model) GetPerformanceInfo() PerformanceInfo {
 	var memStats runtime.MemStats
 	runtime.ReadMemStats(&memStats)
 
-	totalTodos := len(m.todayTodos) + len(m.upcomingTodos) + len(m.generalTodos)
+	totalTodos := len(m.todayTodos) + len(m.overdueTodos) + len(m.upcomingTodos) + len(m.generalTodos)
 
 	return PerformanceInfo{
 		MemoryUsage:    memStats.Alloc / 1024, // KB
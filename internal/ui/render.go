@@ -2,21 +2,34 @@ package ui
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+
+	"github.com/WasathTheekshana/tedo/internal/config"
+	"github.com/WasathTheekshana/tedo/internal/models"
 )
 
 // renderHeader renders the top navigation bar
 func (m Model) renderHeader() string {
 	var tabs []string
 
-	// Update to include all four views
-	views := []ViewType{TodayView, UpcomingView, CalendarView, GeneralView}
+	current := m.currentTab()
+	for i, t := range m.tabs() {
+		name := getViewName(t.view)
+		if t.view == SavedView {
+			name = "🔎 " + m.savedViews[t.saved].Name
+		}
+		overdue := t.view == OverdueView && m.overdueCount() > 0
+		if overdue {
+			name += fmt.Sprintf(" (%d)", m.overdueCount())
+		}
 
-	for _, view := range views {
-		name := getViewName(view)
-		if view == m.currentView {
+		switch {
+		case i == current:
 			tabs = append(tabs, activeTabStyle.Render(name))
-		} else {
+		case overdue:
+			tabs = append(tabs, inactiveTabStyle.Foreground(warningColor).Render(name))
+		default:
 			tabs = append(tabs, inactiveTabStyle.Render(name))
 		}
 	}
@@ -27,6 +40,11 @@ func (m Model) renderHeader() string {
 
 // renderFooter renders the bottom help bar
 func (m Model) renderFooter() string {
+	// An open dialog is answered before anything else
+	if len(m.dialogs) > 0 {
+		return m.renderDialogFooter()
+	}
+
 	// Different help text based on input mode
 	if m.inputState.mode != NavigationMode {
 		help := []string{
@@ -34,6 +52,48 @@ func (m Model) renderFooter() string {
 			"enter: save",
 			"esc: cancel",
 		}
+		switch m.inputState.mode {
+		case RescheduleMode, ChecklistItemMode:
+			help = help[1:]
+		case TagFilterMode:
+			help = []string{"enter: apply", "esc: cancel"}
+		case SavedViewMode:
+			help = []string{"tab: name/query", "enter: save view", "esc: cancel"}
+		case MoveListMode:
+			help = []string{"tab: next list", "enter: move", "esc: cancel"}
+		}
+		return footerStyle.Render(strings.Join(help, " • "))
+	}
+
+	// Typing a search narrows the list as it goes
+	if m.searching {
+		help := []string{"type to search title and description", "↑/↓: move", "enter: keep", "esc: clear"}
+		return footerStyle.Render(strings.Join(help, " • "))
+	}
+
+	// Picking a blocker leaves the views usable to find it
+	if m.linkingID != "" {
+		prompt := fmt.Sprintf("Pick what %q waits on:", m.linkingTitle)
+		help := []string{"move to a todo on any tab", "b/enter: toggle blocker", "esc: cancel"}
+		return footerStyle.Render(warningStyle.Render(prompt) + " " + strings.Join(help, " • "))
+	}
+
+	// Visual mode acts on the marked todos
+	if m.visual {
+		return m.renderVisualFooter()
+	}
+
+	// Help while a todo's checklist is shown
+	if m.expandedTodo() != nil {
+		help := []string{
+			"j/k: navigate items",
+			"x: toggle item",
+			"a: add item",
+			"e: edit item",
+			"d: delete item",
+			"enter/esc: close",
+			"q: quit",
+		}
 		return footerStyle.Render(strings.Join(help, " • "))
 	}
 
@@ -46,6 +106,80 @@ func (m Model) renderFooter() string {
 			"enter: view date",
 			"i: add",
 			"←/→: switch tabs",
+			"u: undo",
+			"q: quit",
+		}
+		return footerStyle.Render(strings.Join(help, " • "))
+	}
+
+	// Help for the list picker on the General tab
+	if m.currentView == GeneralView && m.currentList == "" {
+		help := []string{
+			"j/k: navigate",
+			"enter: open",
+			"n: new list",
+			"r: rename",
+			"a: archive",
+			"d: delete",
+			"←/→: switch tabs",
+			"u: undo",
+			"q: quit",
+		}
+		return footerStyle.Render(strings.Join(help, " • "))
+	}
+
+	// Help for the Overdue tab, where todos are moved back to today
+	if m.currentView == OverdueView {
+		help := []string{
+			"j/k: navigate",
+			"←/→: switch tabs",
+			"x: toggle",
+			"t: move to today",
+			"T: move all to today",
+			"e: edit",
+			"d: delete",
+			"r: reschedule",
+			"s: status",
+			"/: search",
+			"#: filter tags",
+			"u: undo",
+			"q: quit",
+		}
+		return footerStyle.Render(strings.Join(help, " • "))
+	}
+
+	// Help for a saved view, which is managed from its own tab
+	if m.currentView == SavedView {
+		help := []string{
+			"j/k: navigate",
+			"←/→: switch tabs",
+			"x: toggle",
+			"e: edit",
+			"d: delete",
+			"r: reschedule",
+			"p: priority",
+			"s: status",
+			"/: search",
+			"E: edit view",
+			"[/]: move tab",
+			"X: remove view",
+			"u: undo",
+			"q: quit",
+		}
+		return footerStyle.Render(strings.Join(help, " • "))
+	}
+
+	// Help for the Trash tab, where deleted todos wait to be restored
+	if m.currentView == TrashView {
+		help := []string{
+			"j/k: navigate",
+			"←/→: switch tabs",
+			"r/enter: restore",
+			"d: delete forever",
+			"E: empty trash",
+			"/: search",
+			"#: filter tags",
+			"u: undo",
 			"q: quit",
 		}
 		return footerStyle.Render(strings.Join(help, " • "))
@@ -56,12 +190,25 @@ func (m Model) renderFooter() string {
 		"j/k: navigate",
 		"←/→: switch tabs",
 		"x: toggle",
+		"enter: checklist",
 		"d: delete",
 		"e: edit",
+		"r: reschedule",
+		"p: priority",
+		"s: status",
+		"/: search",
+		"#: filter tags",
+		"m: move to list",
+		"b: blocked by",
+		"+: save view",
 		"i: add",
 		"c: calendar",
+		"u: undo",
 		"q: quit",
 	}
+	if m.currentView == UpcomingView {
+		help = append(help[:len(help)-1], "H: horizon", "q: quit")
+	}
 
 	return footerStyle.Render(strings.Join(help, " • "))
 }
@@ -73,59 +220,11 @@ func (m Model) renderTodayView() string {
 		return m.renderInputForm()
 	}
 
-	paginatedTodos, currentPage, totalPages := m.getPaginatedTodos()
-
-	if len(m.todayTodos) == 0 {
-		return baseStyle.Render(
-			fmt.Sprintf("📅 %s\n\nNo todos for today!\n\nPress 'i' to add a new todo.", m.selectedDate),
-		)
-	}
-
-	var items []string
-
-	// Header with pagination info
-	header := fmt.Sprintf("📅 %s", m.selectedDate)
-	if totalPages > 1 {
-		header += fmt.Sprintf(" (Page %d/%d - %d total)", currentPage+1, totalPages, len(m.todayTodos))
-	} else {
-		header += fmt.Sprintf(" (%d todos)", len(m.todayTodos))
-	}
-	items = append(items, header+"\n")
-
-	for i, todo := range paginatedTodos {
-		cursor := " "
-		if i == m.cursor {
-			cursor = ">"
-		}
-
-		checkbox := "☐"
-		style := normalItemStyle
-		if todo.Completed {
-			checkbox = "✓"
-			style = completedItemStyle
-		}
-
-		if i == m.cursor {
-			style = selectedItemStyle
-		}
-
-		// Show absolute index
-		absoluteIndex := currentPage*TodosPerPage + i + 1
-		line := fmt.Sprintf("%s %s %d. %s", cursor, checkbox, absoluteIndex, todo.Title)
-		if todo.Description != "" {
-			line += fmt.Sprintf("\n      %s", todo.Description)
-		}
-
-		items = append(items, style.Render(line))
-	}
-
-	// Add pagination help if needed
-	if totalPages > 1 {
-		items = append(items, "")
-		items = append(items, mutedStyle.Render("Navigation: j/k=item, Ctrl+f/b=page"))
-	}
-
-	return baseStyle.Render(strings.Join(items, "\n"))
+	return m.renderTodoList(
+		fmt.Sprintf("📅 %s", m.selectedDate),
+		"No todos for today!\n\nPress 'i' to add a new todo.",
+		dateHidden,
+	)
 }
 
 func (m Model) renderUpcomingView() string {
@@ -134,61 +233,11 @@ func (m Model) renderUpcomingView() string {
 		return m.renderInputForm()
 	}
 
-	paginatedTodos, currentPage, totalPages := m.getPaginatedTodos()
-
-	if len(m.upcomingTodos) == 0 {
-		return baseStyle.Render("📅 Upcoming Todos\n\nNo upcoming todos!\n\nPress 'i' to add a new todo or 'c' for calendar.")
-	}
-
-	var items []string
-
-	// Header with pagination info
-	header := "📅 Upcoming Todos"
-	if totalPages > 1 {
-		header += fmt.Sprintf(" (Page %d/%d - %d total)", currentPage+1, totalPages, len(m.upcomingTodos))
-	} else {
-		header += fmt.Sprintf(" (%d todos)", len(m.upcomingTodos))
-	}
-	items = append(items, header+"\n")
-
-	for i, todo := range paginatedTodos {
-		cursor := " "
-		if i == m.cursor {
-			cursor = ">"
-		}
-
-		checkbox := "☐"
-		style := normalItemStyle
-		if todo.Completed {
-			checkbox = "✓"
-			style = completedItemStyle
-		}
-
-		if i == m.cursor {
-			style = selectedItemStyle
-		}
-
-		// Show date and absolute index
-		absoluteIndex := currentPage*TodosPerPage + i + 1
-		dateStr := ""
-		if todo.Date != nil {
-			dateStr = fmt.Sprintf(" (%s)", *todo.Date)
-		}
-		line := fmt.Sprintf("%s %s %d. %s%s", cursor, checkbox, absoluteIndex, todo.Title, dateStr)
-		if todo.Description != "" {
-			line += fmt.Sprintf("\n      %s", todo.Description)
-		}
-
-		items = append(items, style.Render(line))
-	}
-
-	// Add pagination help if needed
-	if totalPages > 1 {
-		items = append(items, "")
-		items = append(items, mutedStyle.Render("Navigation: j/k=item, Ctrl+f/b=page"))
-	}
-
-	return baseStyle.Render(strings.Join(items, "\n"))
+	return m.renderTodoList(
+		"📅 Upcoming Todos · "+config.FormatUpcomingDays(m.cfg.UpcomingDays),
+		"No upcoming todos!\n\nPress 'i' to add a new todo, 'H' to look further ahead or 'c' for calendar.",
+		dateInline,
+	)
 }
 
 // renderCalendarView renders the calendar view (placeholder for now)
@@ -209,55 +258,123 @@ func (m Model) renderCalendarView() string {
 	return baseStyle.Render(calendar + strings.Join(help, "\n"))
 }
 
-// renderGeneralView renders the general todos view
+// renderGeneralView renders the list picker or the todos of the open list
 func (m Model) renderGeneralView() string {
 	// If in input mode, show the input form
 	if m.inputState.mode != NavigationMode {
 		return m.renderInputForm()
 	}
 
+	if m.currentList == "" {
+		return m.renderListPicker()
+	}
+
+	return m.renderTodoList(
+		"📝 "+m.listName(m.currentList),
+		"No todos in this list!\n\nPress 'i' to add a new todo or esc to pick another list.",
+		dateHidden,
+	)
+}
+
+// dateDisplay decides how renderTodoList shows the dates of todos
+type dateDisplay int
+
+const (
+	dateHidden  dateDisplay = iota // all todos share a day, or have none
+	dateInline                     // after each todo's title
+	dateGrouped                    // as a heading above each day's todos
+)
+
+// renderTodoList renders the current page of the visible todos under header.
+// empty is shown when the view has no todos at all; dates says how each
+// todo's date is shown.
+func (m Model) renderTodoList(header, empty string, dates dateDisplay) string {
 	paginatedTodos, currentPage, totalPages := m.getPaginatedTodos()
+	total := len(m.visibleTodos())
 
-	if len(m.generalTodos) == 0 {
-		return baseStyle.Render("📝 General Todos\n\nNo general todos!\n\nPress 'i' to add a new todo.")
+	if len(m.listTodos()) == 0 {
+		return baseStyle.Render(header + "\n\n" + empty)
 	}
 
 	var items []string
 
 	// Header with pagination info
-	header := "📝 General Todos"
 	if totalPages > 1 {
-		header += fmt.Sprintf(" (Page %d/%d - %d total)", currentPage+1, totalPages, len(m.generalTodos))
+		header += fmt.Sprintf(" (Page %d/%d - %d total)", currentPage+1, totalPages, total)
 	} else {
-		header += fmt.Sprintf(" (%d todos)", len(m.generalTodos))
+		header += fmt.Sprintf(" (%d todos)", total)
+	}
+	if m.visual {
+		header += warningStyle.Render(fmt.Sprintf(" · %d selected", len(m.markedTodos())))
+	}
+	if m.isFiltered() || m.searching {
+		header += "\n" + m.renderFilterLine()
 	}
 	items = append(items, header+"\n")
 
+	if total == 0 {
+		items = append(items, mutedStyle.Render("No todos match the filter. Press esc to clear it."))
+	}
+
+	day := ""
 	for i, todo := range paginatedTodos {
+		if dates == dateGrouped && todo.Date != nil && *todo.Date != day {
+			if day != "" {
+				items = append(items, "")
+			}
+			day = *todo.Date
+			items = append(items, m.renderDayHeading(day))
+		}
+
 		cursor := " "
 		if i == m.cursor {
 			cursor = ">"
 		}
 
-		checkbox := "☐"
+		checkbox := renderStatus(todo.CurrentStatus())
 		style := normalItemStyle
 		if todo.Completed {
-			checkbox = "✓"
 			style = completedItemStyle
 		}
 
+		// Blocked todos are dimmed and name what they wait on
+		blockers := m.deps.OpenBlockers(todo)
+		if todo.Completed {
+			blockers = nil
+		}
+		if len(blockers) > 0 {
+			checkbox = "⧗"
+			style = blockedItemStyle
+		}
+
 		if i == m.cursor {
 			style = selectedItemStyle
 		}
 
-		// Show absolute index
+		// Show date and absolute index
 		absoluteIndex := currentPage*TodosPerPage + i + 1
-		line := fmt.Sprintf("%s %s %d. %s", cursor, checkbox, absoluteIndex, todo.Title)
+		dateStr := ""
+		if dates == dateInline && todo.Date != nil {
+			dateStr = fmt.Sprintf(" (%s)", *todo.Date)
+		}
+		if m.visual {
+			cursor += " " + m.renderMark(todo)
+		}
+		line := fmt.Sprintf("%s %s %d. %s%s%s%s", cursor, checkbox, absoluteIndex, renderPriorityPrefix(todo), m.highlightSearch(todo.Title), dateStr, renderTodoBadges(todo))
+		if blockedBy := renderBlockedBy(blockers); blockedBy != "" {
+			line += " " + blockedBy
+		}
+		if todo.DeletedAt != nil {
+			line += " " + m.renderDeletedFrom(todo)
+		}
 		if todo.Description != "" {
-			line += fmt.Sprintf("\n      %s", todo.Description)
+			line += fmt.Sprintf("\n      %s", m.highlightSearch(todo.Description))
 		}
 
 		items = append(items, style.Render(line))
+		if i == m.cursor && todo.ID == m.expandedID {
+			items = append(items, m.renderChecklist(todo))
+		}
 	}
 
 	// Add pagination help if needed
@@ -269,42 +386,157 @@ func (m Model) renderGeneralView() string {
 	return baseStyle.Render(strings.Join(items, "\n"))
 }
 
+// renderFilterLine describes the active list filters
+func (m Model) renderFilterLine() string {
+	var chips []string
+	if m.search != "" || m.searching {
+		query := "/" + m.search
+		if m.searching {
+			query += "█"
+		}
+		chips = append(chips, matchStyle.Render(query))
+	}
+	for _, tag := range m.tagFilter {
+		chips = append(chips, renderTag(tag))
+	}
+	return mutedStyle.Render("Filter: ") + strings.Join(chips, " ") + mutedStyle.Render("  (/: search, #: tags, esc: clear)")
+}
+
+// renderPriorityPrefix renders the priority marker shown before a todo's title
+func renderPriorityPrefix(todo models.Todo) string {
+	if marker := renderPriority(todo.Priority); marker != "" {
+		return marker + " "
+	}
+	return ""
+}
+
+// renderTodoBadges renders the markers shown after a todo's title
+func renderTodoBadges(todo models.Todo) string {
+	var badges []string
+	if progress := renderChecklistProgress(todo); progress != "" {
+		badges = append(badges, progress)
+	}
+	if todo.IsRecurring() {
+		badges = append(badges, mutedStyle.Render("↻ "+todo.Recurrence.String()))
+	}
+	if carried := renderCarriedFrom(todo); carried != "" {
+		badges = append(badges, carried)
+	}
+	for _, tag := range todo.Tags {
+		badges = append(badges, renderTag(tag))
+	}
+
+	if len(badges) == 0 {
+		return ""
+	}
+	return " " + strings.Join(badges, " ")
+}
+
 // renderInputForm renders the input form for adding/editing todos
 func (m Model) renderInputForm() string {
 	var title string
-	if m.inputState.mode == AddTodoMode {
+	switch m.inputState.mode {
+	case AddTodoMode:
 		title = "➕ Add New Todo"
-	} else {
+	case RescheduleMode:
+		title = "📅 Reschedule Todo"
+	default:
 		title = "✏️ Edit Todo"
 	}
 
 	// Show any errors
 	errorDisplay := m.renderError()
 
-	// Render title field with character count
-	titleLabel := fmt.Sprintf("Title (%d/100):", len(m.inputState.title))
-	titleValue := m.inputState.title
+	// Render date field with a preview of the resolved date
+	dateLabel := m.renderFieldLabel("Date (empty = no date):", dateField)
+	dateValue := m.renderFieldValue(m.inputState.date, dateField)
+	if date, err := ParseDateInput(m.inputState.date); err != nil {
+		dateValue += "  " + mutedStyle.Render("(not a date yet)")
+	} else if date == nil {
+		dateValue += "  " + mutedStyle.Render("→ list "+m.listName(m.inputListID()))
+	} else if *date != strings.TrimSpace(m.inputState.date) {
+		dateValue += "  " + mutedStyle.Render("→ "+*date)
+	}
 
-	if m.inputState.editField == 0 {
-		if m.inputState.cursor <= len(titleValue) {
-			titleValue = titleValue[:m.inputState.cursor] + "│" + titleValue[m.inputState.cursor:]
+	// Render tags field with the chips it will produce
+	tagsLabel := m.renderFieldLabel("Tags (e.g. #work #home):", tagsField)
+	tagsValue := m.renderFieldValue(m.inputState.tags, tagsField)
+	if tags, err := models.ParseTags(m.inputState.tags); err != nil {
+		tagsValue += "  " + mutedStyle.Render("(invalid tag)")
+	} else if len(tags) > 0 {
+		var chips []string
+		for _, tag := range tags {
+			chips = append(chips, renderTag(tag))
 		}
-		titleLabel = selectedItemStyle.Render(titleLabel)
-	} else {
-		titleLabel = normalItemStyle.Render(titleLabel)
+		tagsValue += "  " + strings.Join(chips, " ")
 	}
 
-	// Render description field with character count
-	descLabel := fmt.Sprintf("Description (%d/500):", len(m.inputState.description))
-	descValue := m.inputState.description
+	switch m.inputState.mode {
+	case MoveListMode, ChecklistItemMode:
+		return m.renderPromptForm(errorDisplay)
+	case SavedViewMode:
+		return m.renderSavedViewForm(errorDisplay)
+	}
 
-	if m.inputState.editField == 1 {
-		if m.inputState.cursor <= len(descValue) {
-			descValue = descValue[:m.inputState.cursor] + "│" + descValue[m.inputState.cursor:]
+	if m.inputState.mode == TagFilterMode {
+		form := []string{
+			"🏷 Filter by Tags",
+			"",
+			errorDisplay,
+			tagsLabel,
+			"  " + tagsValue,
+			"",
 		}
-		descLabel = selectedItemStyle.Render(descLabel)
-	} else {
-		descLabel = normalItemStyle.Render(descLabel)
+		if known := m.knownTags(); len(known) > 0 {
+			form = append(form, mutedStyle.Render("Tags in this list: "+models.FormatTags(known)))
+		}
+		form = append(form,
+			mutedStyle.Render("Only todos with all of these tags are shown. Leave empty to show everything."),
+			mutedStyle.Render("Enter/Ctrl+S: apply • Esc: cancel"),
+		)
+		return baseStyle.Render(strings.Join(form, "\n"))
+	}
+
+	if m.inputState.mode == RescheduleMode {
+		form := []string{
+			title,
+			"",
+			errorDisplay,
+			normalItemStyle.Render(m.inputState.title),
+			"",
+			dateLabel,
+			"  " + dateValue,
+			"",
+			mutedStyle.Render("Dates: YYYY-MM-DD, today, tomorrow, next fri, in 3 days, +2w, eom, nov 3"),
+			mutedStyle.Render("Enter/Ctrl+S: save • Esc: cancel"),
+		}
+		return baseStyle.Render(strings.Join(form, "\n"))
+	}
+
+	// Render title field with character count
+	titleLabel := m.renderFieldLabel(fmt.Sprintf("Title (%d/100):", len(m.inputState.title)), titleField)
+	titleValue := m.renderFieldValue(m.inputState.title, titleField)
+
+	// Render description field with character count
+	descLabel := m.renderFieldLabel(fmt.Sprintf("Description (%d/500):", len(m.inputState.description)), descriptionField)
+	descValue := m.renderFieldValue(m.inputState.description, descriptionField)
+
+	// Render priority field with a preview of the parsed value
+	priorityLabel := m.renderFieldLabel("Priority (none/low/medium/high):", priorityField)
+	priorityValue := m.renderFieldValue(m.inputState.priority, priorityField)
+	if priority, err := models.ParsePriority(m.inputState.priority); err != nil {
+		priorityValue += "  " + mutedStyle.Render("(unknown priority)")
+	} else if marker := renderPriority(priority); marker != "" {
+		priorityValue += "  " + marker
+	}
+
+	// Render repeat field with a preview of the parsed rule
+	repeatLabel := m.renderFieldLabel("Repeat (empty = once):", repeatField)
+	repeatValue := m.renderFieldValue(m.inputState.repeat, repeatField)
+	if rule, err := ParseRepeatInput(m.inputState.repeat); err != nil {
+		repeatValue += "  " + mutedStyle.Render("(not a rule yet)")
+	} else if rule != nil && rule.String() != strings.TrimSpace(m.inputState.repeat) {
+		repeatValue += "  " + mutedStyle.Render("→ "+rule.String())
 	}
 
 	// Build the form
@@ -318,12 +550,100 @@ func (m Model) renderInputForm() string {
 		descLabel,
 		"  " + descValue,
 		"",
+		priorityLabel,
+		"  " + priorityValue,
+		"",
+		tagsLabel,
+		"  " + tagsValue,
+		"",
+		dateLabel,
+		"  " + dateValue,
+		"",
+		repeatLabel,
+		"  " + repeatValue,
+		"",
+		mutedStyle.Render("Dates: YYYY-MM-DD, today, tomorrow, next fri, in 3 days, +2w, eom, nov 3"),
+		mutedStyle.Render("Repeat: daily, weekdays, every mon,wed, every 3 days, monthly on 1, monthly on last fri, ... until DATE, x10"),
 		mutedStyle.Render("Tab: switch field • Enter/Ctrl+S: save • Esc: cancel • Ctrl+A: select all"),
 	}
 
 	return baseStyle.Render(strings.Join(form, "\n"))
 }
 
+// renderPromptForm renders the single line prompts for naming a list,
+// choosing the list to move a todo to and writing a checklist item
+func (m Model) renderPromptForm(errorDisplay string) string {
+	title := "📦 Move to List"
+	label := "List:"
+	hint := "Tab: next list • Enter/Ctrl+S: move • Esc: cancel"
+	if m.inputState.mode == ChecklistItemMode {
+		title = "☑ Add Checklist Item"
+		if m.inputState.editingItem >= 0 {
+			title = "☑ Edit Checklist Item"
+		}
+		label = "Item:"
+		hint = "Enter/Ctrl+S: save • Esc: cancel"
+	}
+
+	form := []string{
+		title,
+		"",
+		errorDisplay,
+	}
+	if todo := m.inputState.editingTodo; todo != nil {
+		form = append(form, normalItemStyle.Render(todo.Title), "")
+	}
+	form = append(form,
+		selectedItemStyle.Render(label),
+		"  "+m.renderFieldValue(m.inputState.title, titleField),
+		"",
+	)
+
+	if m.inputState.mode == MoveListMode {
+		var names []string
+		for _, entry := range m.lists {
+			if !entry.list.Archived {
+				names = append(names, entry.list.Name)
+			}
+		}
+		form = append(form,
+			mutedStyle.Render("Lists: "+strings.Join(names, ", ")),
+			mutedStyle.Render("Todos moved to a list no longer have a date."),
+		)
+	}
+	form = append(form, mutedStyle.Render(hint))
+
+	return baseStyle.Render(strings.Join(form, "\n"))
+}
+
+// inputListID returns the list the todo in the input form is filed in when
+// it has no date
+func (m Model) inputListID() string {
+	if m.inputState.mode == AddTodoMode {
+		return m.inputState.list
+	}
+	if todo := m.inputState.editingTodo; todo != nil {
+		return todo.ListID()
+	}
+	return ""
+}
+
+// renderFieldLabel highlights the label of the field being edited
+func (m Model) renderFieldLabel(label string, field int) string {
+	if m.inputState.editField == field {
+		return selectedItemStyle.Render(label)
+	}
+	return normalItemStyle.Render(label)
+}
+
+// renderFieldValue draws the text cursor into the field being edited
+func (m Model) renderFieldValue(value string, field int) string {
+	if m.inputState.editField == field && m.inputState.cursor <= len(value) {
+		return value[:m.inputState.cursor] + "│" + value[m.inputState.cursor:]
+	}
+	return value
+}
+
 func (m Model) renderError() string {
 	errorMsg := m.errorState.GetError()
 	if errorMsg == "" {
@@ -339,11 +659,33 @@ func (m Model) getCurrentViewContent() string {
 		return m.renderTodayView()
 	case UpcomingView:
 		return m.renderUpcomingView()
+	case OverdueView:
+		return m.renderOverdueView()
 	case CalendarView:
 		return m.renderCalendarView()
 	case GeneralView:
 		return m.renderGeneralView()
+	case SavedView:
+		return m.renderSavedView()
+	case TrashView:
+		return m.renderTrashView()
 	default:
 		return ""
 	}
 }
+
+// knownTags returns the tags used in the current list view, sorted
+func (m Model) knownTags() []string {
+	seen := make(map[string]bool)
+	var tags []string
+	for _, todo := range m.listTodos() {
+		for _, tag := range todo.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
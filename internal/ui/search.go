@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
+
+// matchStyle highlights the characters a search matched
+var matchStyle = lipgloss.NewStyle().
+	Foreground(secondaryColor).
+	Bold(true).
+	Underline(true)
+
+// fuzzyMatch reports whether the characters of pattern appear in text in
+// order, ignoring case, and returns the rune positions they matched
+func fuzzyMatch(pattern, text string) ([]int, bool) {
+	want := []rune(pattern)
+	if len(want) == 0 {
+		return nil, true
+	}
+
+	var positions []int
+	for i, r := range []rune(text) {
+		if unicode.ToLower(r) == unicode.ToLower(want[len(positions)]) {
+			positions = append(positions, i)
+			if len(positions) == len(want) {
+				return positions, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// matchesSearch reports whether every word of the search fuzzy-matches the
+// title or the description of todo
+func (m Model) matchesSearch(todo models.Todo) bool {
+	for _, term := range strings.Fields(m.search) {
+		_, inTitle := fuzzyMatch(term, todo.Title)
+		_, inDescription := fuzzyMatch(term, todo.Description)
+		if !inTitle && !inDescription {
+			return false
+		}
+	}
+	return true
+}
+
+// highlightSearch renders text with the characters matched by the search
+// highlighted
+func (m Model) highlightSearch(text string) string {
+	matched := make(map[int]bool)
+	for _, term := range strings.Fields(m.search) {
+		positions, _ := fuzzyMatch(term, text)
+		for _, i := range positions {
+			matched[i] = true
+		}
+	}
+	if len(matched) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	for i, r := range []rune(text) {
+		if matched[i] {
+			b.WriteString(matchStyle.Render(string(r)))
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// startSearch starts typing a search that narrows the current list view
+func (m Model) startSearch() (tea.Model, tea.Cmd) {
+	m.searching = true
+	m.collapseTodo()
+	return m, nil
+}
+
+// setSearch narrows every list view to todos matching query and moves back
+// to the first page
+func (m *Model) setSearch(query string) {
+	m.search = query
+	m.todayPage = 0
+	m.upcomingPage = 0
+	m.overduePage = 0
+	m.generalPage = 0
+	m.trashPage = 0
+	for i := range m.savedViews {
+		m.savedViews[i].page = 0
+	}
+	m.cursor = 0
+}
+
+// handleSearchKeys handles keys while a search is typed. The list narrows
+// with every key.
+func (m Model) handleSearchKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+	case tea.KeyEnter:
+		m.searching = false
+		m.search = strings.TrimSpace(m.search)
+	case tea.KeyEsc:
+		m.searching = false
+		m.setSearch("")
+	case tea.KeyBackspace:
+		if runes := []rune(m.search); len(runes) > 0 {
+			m.setSearch(string(runes[:len(runes)-1]))
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.setSearch(m.search + string(msg.Runes))
+	}
+	return m, nil
+}
+
+// clearFilters removes the search and the tag filter
+func (m *Model) clearFilters() {
+	m.setSearch("")
+	m.setTagFilter(nil)
+}
@@ -1,6 +1,12 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"hash/fnv"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+)
 
 var (
 	// Color palette
@@ -43,6 +49,38 @@ var (
 
 	normalItemStyle = lipgloss.NewStyle()
 
+	// Blocked todos are dimmed until their blockers are done
+	blockedItemStyle = lipgloss.NewStyle().
+				Foreground(mutedColor).
+				Faint(true)
+
+	blockedStyle = lipgloss.NewStyle().
+			Foreground(warningColor)
+
+	// Priority markers
+	priorityHighStyle = lipgloss.NewStyle().
+				Foreground(errorColor).
+				Bold(true)
+
+	priorityMediumStyle = lipgloss.NewStyle().
+				Foreground(warningColor).
+				Bold(true)
+
+	priorityLowStyle = lipgloss.NewStyle().
+				Foreground(accentColor)
+
+	// Tag chips are colored by tag so the same tag always looks the same
+	tagColors = []lipgloss.Color{
+		lipgloss.Color("39"),  // Blue
+		lipgloss.Color("170"), // Magenta
+		lipgloss.Color("78"),  // Green
+		lipgloss.Color("208"), // Orange
+		lipgloss.Color("141"), // Purple
+		lipgloss.Color("44"),  // Teal
+		lipgloss.Color("204"), // Rose
+		lipgloss.Color("220"), // Yellow
+	}
+
 	// Accent style for dates with todos
 	accentStyle = lipgloss.NewStyle().
 			Foreground(accentColor).
@@ -58,11 +96,62 @@ var (
 			Foreground(errorColor).
 			Bold(true)
 
+	// Styles for finished checklists and questions in the footer
+	successStyle = lipgloss.NewStyle().
+			Foreground(successColor)
+
+	warningStyle = lipgloss.NewStyle().
+			Foreground(warningColor).
+			Bold(true)
+
 	// Muted style for help text
 	mutedStyle = lipgloss.NewStyle().
 			Foreground(mutedColor)
 )
 
+// renderStatus returns the checkbox marking a todo's status
+func renderStatus(status models.Status) string {
+	switch status {
+	case models.StatusInProgress:
+		return accentStyle.Render("◐")
+	case models.StatusWaiting:
+		return warningStyle.Render("◷")
+	case models.StatusDone:
+		return "✓"
+	case models.StatusCancelled:
+		return "✗"
+	default:
+		return "☐"
+	}
+}
+
+// renderPriority returns the styled marker for a priority, or "" for none
+func renderPriority(priority models.Priority) string {
+	switch priority {
+	case models.PriorityHigh:
+		return priorityHighStyle.Render("!!!")
+	case models.PriorityMedium:
+		return priorityMediumStyle.Render("!!")
+	case models.PriorityLow:
+		return priorityLowStyle.Render("!")
+	default:
+		return ""
+	}
+}
+
+// renderTag returns a tag as a colored chip
+func renderTag(tag string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(tag))
+	color := tagColors[hash.Sum32()%uint32(len(tagColors))]
+
+	return lipgloss.NewStyle().
+		Background(color).
+		Foreground(lipgloss.Color("0")).
+		Padding(0, 1).
+		Render("#" + tag)
+}
+
 // getViewName returns the display name for a view type
 func getViewName(view ViewType) string {
 	switch view {
@@ -70,10 +159,16 @@ func getViewName(view ViewType) string {
 		return "Today"
 	case UpcomingView:
 		return "Upcoming"
+	case OverdueView:
+		return "Overdue"
 	case CalendarView:
 		return "Calendar"
 	case GeneralView:
-		return "General"
+		return "Lists"
+	case SavedView:
+		return "Saved View"
+	case TrashView:
+		return "Trash"
 	default:
 		return "Unknown"
 	}
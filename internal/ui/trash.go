@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// loadTrashedTodos loads the deleted todos, most recently deleted first
+func loadTrashedTodos(repo *storage.Repository) []models.Todo {
+	todos, _ := repo.TrashedTodos()
+	return todos
+}
+
+// purgeTrash permanently deletes the todos kept in the trash for longer than
+// the configured retention
+func (m *Model) purgeTrash() {
+	if m.cfg.TrashDays <= 0 {
+		return
+	}
+
+	purged, err := m.repository.PurgeTrash(time.Now().AddDate(0, 0, -m.cfg.TrashDays))
+	if err != nil {
+		m.errorState.SetError(err)
+		return
+	}
+	if purged > 0 {
+		m.trashTodos = loadTrashedTodos(m.repository)
+		m.resetPagination()
+	}
+}
+
+// handleTrashViewKeys handles keys specific to the Trash view
+func (m Model) handleTrashViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	paginatedTodos, currentPage, totalPages := m.getPaginatedTodos()
+
+	switch msg.String() {
+	case "j", "down":
+		if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos)-1 {
+			m.cursor++
+		} else if len(paginatedTodos) > 0 && m.cursor == len(paginatedTodos)-1 && currentPage < totalPages-1 {
+			m.trashPage++
+			m.cursor = 0
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		} else if m.cursor == 0 && currentPage > 0 {
+			m.trashPage--
+			newPaginatedTodos, _, _ := m.getPaginatedTodos()
+			m.cursor = len(newPaginatedTodos) - 1
+		}
+	case "ctrl+f", "page_down":
+		if currentPage < totalPages-1 {
+			m.trashPage++
+			m.cursor = 0
+		}
+	case "ctrl+b", "page_up":
+		if currentPage > 0 {
+			m.trashPage--
+			m.cursor = 0
+		}
+	case "r", "enter":
+		return m.restoreCurrentTodo()
+	case "d":
+		return m.purgeCurrentTodo()
+	case "E":
+		return m.emptyTrash()
+	case "/":
+		return m.startSearch()
+	case "#":
+		m.inputState.StartTagFilterMode(m.tagFilter)
+		return m, nil
+	case "esc":
+		m.clearFilters()
+		return m, nil
+	case "c":
+		return m.showTab(tab{view: CalendarView}), nil
+	}
+	return m, nil
+}
+
+// restoreCurrentTodo moves the todo under the cursor back out of the trash
+func (m Model) restoreCurrentTodo() (tea.Model, tea.Cmd) {
+	todo := m.currentTodo()
+	if todo == nil {
+		return m, nil
+	}
+
+	restored, err := m.repository.RestoreTodo(todo.ID)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	m.lastRefresh = time.Time{}
+	m.reloadTodos()
+	m.resetPagination()
+	m.errorState.SetNotice(fmt.Sprintf("Restored %q to %s", restored.Title, m.origin(restored)))
+	return m, nil
+}
+
+// purgeCurrentTodo permanently deletes the todo under the cursor
+func (m Model) purgeCurrentTodo() (tea.Model, tea.Cmd) {
+	todo := m.currentTodo()
+	if todo == nil {
+		return m, nil
+	}
+
+	id := todo.ID
+	question := fmt.Sprintf("Delete %q for good?", todo.Title)
+	return m.confirmDeletion("Delete for good", question, true, func(m Model) (tea.Model, tea.Cmd) {
+		return m.purgeTodo(id)
+	})
+}
+
+// purgeTodo permanently deletes the todo with the given ID from the trash
+func (m Model) purgeTodo(id string) (tea.Model, tea.Cmd) {
+	purged, err := m.repository.PurgeTodo(id)
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	m.trashTodos = loadTrashedTodos(m.repository)
+	m.resetPagination()
+	m.errorState.SetNotice(fmt.Sprintf("Deleted %q for good", purged.Title))
+	return m, nil
+}
+
+// emptyTrash permanently deletes every todo in the trash
+func (m Model) emptyTrash() (tea.Model, tea.Cmd) {
+	if len(m.trashTodos) == 0 {
+		return m, nil
+	}
+
+	question := fmt.Sprintf("Delete all %d todo(s) in the trash for good?", len(m.trashTodos))
+	return m.confirmDeletion("Empty trash", question, true, func(m Model) (tea.Model, tea.Cmd) {
+		return m.purgeAllTrash()
+	})
+}
+
+// purgeAllTrash permanently deletes every todo in the trash
+func (m Model) purgeAllTrash() (tea.Model, tea.Cmd) {
+	emptied, err := m.repository.EmptyTrash()
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	m.trashTodos = loadTrashedTodos(m.repository)
+	m.resetPagination()
+	m.errorState.SetNotice(fmt.Sprintf("Deleted %d todo(s) for good", emptied))
+	return m, nil
+}
+
+// origin names where a todo lives: its date, or its list
+func (m Model) origin(todo models.Todo) string {
+	if todo.Date != nil {
+		return *todo.Date
+	}
+	return m.listName(todo.ListID())
+}
+
+// renderDeletedFrom renders where a trashed todo came from and when it was
+// deleted, e.g. "🗑 from Work · deleted 3 days ago"
+func (m Model) renderDeletedFrom(todo models.Todo) string {
+	badge := "🗑 from " + m.origin(todo)
+	if today, err := time.Parse("2006-01-02", m.today); err == nil {
+		deleted, _ := time.Parse("2006-01-02", todo.DeletedAt.Format("2006-01-02"))
+		switch days := int(today.Sub(deleted).Hours() / 24); days {
+		case 0:
+			badge += " · deleted today"
+		case 1:
+			badge += " · deleted yesterday"
+		default:
+			badge += fmt.Sprintf(" · deleted %d days ago", days)
+		}
+	}
+	return mutedStyle.Render(badge)
+}
+
+// renderTrashView renders the deleted todos
+func (m Model) renderTrashView() string {
+	header := "🗑 Trash"
+	if m.cfg.TrashDays > 0 {
+		header += mutedStyle.Render(fmt.Sprintf("  kept for %d days", m.cfg.TrashDays))
+	}
+
+	return m.renderTodoList(
+		header,
+		"Trash is empty!\n\nDeleted todos are kept here until they are restored or purged.",
+		dateHidden,
+	)
+}
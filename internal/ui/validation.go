@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"strings"
 	"unicode"
+
+	"github.com/WasathTheekshana/tedo/internal/dateparse"
+	"github.com/WasathTheekshana/tedo/internal/models"
 )
 
 // ValidationError represents a validation error
@@ -77,3 +80,28 @@ func CleanInput(input string) string {
 
 	return cleaned
 }
+
+// dates resolves the date and repeat fields of the input form, so both read
+// the same clock
+var dates = dateparse.New(nil)
+
+// ParseDateInput resolves the date field of the input form. An empty field
+// means a general todo and returns nil.
+func ParseDateInput(input string) (*string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, nil
+	}
+
+	date, err := dates.ParseString(input)
+	if err != nil {
+		return nil, err
+	}
+	return &date, nil
+}
+
+// ParseRepeatInput resolves the repeat field of the input form. An empty
+// field means a one-off todo and returns nil.
+func ParseRepeatInput(input string) (*models.Recurrence, error) {
+	return dates.ParseRecurrence(strings.TrimSpace(input))
+}
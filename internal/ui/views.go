@@ -0,0 +1,350 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/config"
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/query"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// savedView is a saved query shown as its own tab. Each one keeps its own
+// page and cursor.
+type savedView struct {
+	config.View
+	query  *query.Query
+	err    error // why the query could not be parsed or run
+	todos  []models.Todo
+	page   int
+	cursor int
+}
+
+// loadSavedViews parses the saved views and loads their todos
+func loadSavedViews(repo *storage.Repository, views []config.View) []savedView {
+	saved := make([]savedView, len(views))
+	for i, view := range views {
+		saved[i].View = view
+		saved[i].load(repo)
+	}
+	return saved
+}
+
+// load runs the view's query again
+func (v *savedView) load(repo *storage.Repository) {
+	v.todos = nil
+	v.query, v.err = query.Parse(v.Query)
+	if v.err != nil {
+		return
+	}
+	v.todos, v.err = repo.Query(v.query, time.Now())
+}
+
+// tab is one tab of the header: a built-in view, or the saved view at
+// index saved
+type tab struct {
+	view  ViewType
+	saved int
+}
+
+// tabs returns every tab in header order, the saved views last
+func (m Model) tabs() []tab {
+	tabs := []tab{{view: TodayView}, {view: OverdueView}, {view: UpcomingView}, {view: CalendarView}, {view: GeneralView}}
+	for i := range m.savedViews {
+		tabs = append(tabs, tab{view: SavedView, saved: i})
+	}
+	return append(tabs, tab{view: TrashView})
+}
+
+// currentTab returns the position of the shown tab in tabs
+func (m Model) currentTab() int {
+	for i, t := range m.tabs() {
+		if t.view == m.currentView && (t.view != SavedView || t.saved == m.currentSaved) {
+			return i
+		}
+	}
+	return 0
+}
+
+// showTab switches to t. A saved view gets back the cursor it had when it
+// was left; the other views start at the top.
+func (m Model) showTab(t tab) Model {
+	if view := m.currentSavedView(); view != nil {
+		view.cursor = m.cursor
+	}
+
+	m.exitVisual()
+	m.currentView = t.view
+	m.currentSaved = t.saved
+	m.cursor = 0
+	if view := m.currentSavedView(); view != nil {
+		m.cursor = view.cursor
+	}
+	m.collapseTodo()
+	return m
+}
+
+// currentSavedView returns the saved view being shown, or nil
+func (m *Model) currentSavedView() *savedView {
+	if m.currentView != SavedView || m.currentSaved >= len(m.savedViews) {
+		return nil
+	}
+	return &m.savedViews[m.currentSaved]
+}
+
+// handleSavedViewKeys handles keys specific to saved views
+func (m Model) handleSavedViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	paginatedTodos, currentPage, totalPages := m.getPaginatedTodos()
+	page := m.viewPage()
+
+	switch msg.String() {
+	case "j", "down":
+		if len(paginatedTodos) > 0 && m.cursor < len(paginatedTodos)-1 {
+			m.cursor++
+		} else if len(paginatedTodos) > 0 && m.cursor == len(paginatedTodos)-1 && currentPage < totalPages-1 {
+			*page++
+			m.cursor = 0
+		}
+	case "k", "up":
+		if m.cursor > 0 {
+			m.cursor--
+		} else if m.cursor == 0 && currentPage > 0 {
+			*page--
+			newPaginatedTodos, _, _ := m.getPaginatedTodos()
+			m.cursor = len(newPaginatedTodos) - 1
+		}
+	case "ctrl+f", "page_down":
+		if currentPage < totalPages-1 {
+			*page++
+			m.cursor = 0
+		}
+	case "ctrl+b", "page_up":
+		if currentPage > 0 {
+			*page--
+			m.cursor = 0
+		}
+	case "x":
+		return m.toggleCurrentTodo(), nil
+	case "v":
+		return m.startVisual()
+	case "enter":
+		return m.expandCurrentTodo()
+	case "e":
+		return m.editCurrentTodo()
+	case "d":
+		return m.deleteCurrentTodo()
+	case "r":
+		return m.rescheduleCurrentTodo()
+	case "p":
+		return m.cyclePriorityCurrentTodo()
+	case "s":
+		return m.cycleStatusCurrentTodo()
+	case "/":
+		return m.startSearch()
+	case "#":
+		m.inputState.StartTagFilterMode(m.tagFilter)
+		return m, nil
+	case "m":
+		return m.moveCurrentTodo()
+	case "b":
+		return m.startPickingBlocker()
+	case "B":
+		return m.clearBlockersCurrentTodo()
+	case "+":
+		return m.startSavingView()
+	case "E":
+		m.inputState.StartSavedViewMode(m.currentSaved, m.savedViews[m.currentSaved].View)
+		return m, nil
+	case "X":
+		return m.removeCurrentView()
+	case "[":
+		return m.moveCurrentView(-1)
+	case "]":
+		return m.moveCurrentView(1)
+	case "esc":
+		m.clearFilters()
+		return m, nil
+	case "c":
+		return m.showTab(tab{view: CalendarView}), nil
+	}
+	return m, nil
+}
+
+// startSavingView starts saving a new view, with a query built from the
+// filters currently applied
+func (m Model) startSavingView() (tea.Model, tea.Cmd) {
+	var terms []string
+	for _, tag := range m.tagFilter {
+		terms = append(terms, "tag:"+tag)
+	}
+	for _, word := range strings.Fields(m.search) {
+		terms = append(terms, fmt.Sprintf("text:%q", word))
+	}
+
+	m.inputState.StartSavedViewMode(-1, config.View{Query: strings.Join(terms, " ")})
+	return m, nil
+}
+
+// saveView stores the view being edited and shows it
+func (m Model) saveView() (tea.Model, tea.Cmd) {
+	view := config.View{
+		Name:  CleanInput(m.inputState.title),
+		Query: strings.TrimSpace(m.inputState.description),
+	}
+	if view.Name == "" {
+		m.errorState.SetErrorMessage("name is required")
+		return m, nil
+	}
+	if _, err := query.Parse(view.Query); err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+
+	saved := savedView{View: view}
+	saved.load(m.repository)
+
+	i := m.inputState.editingView
+	if i >= 0 && i < len(m.savedViews) {
+		m.savedViews[i] = saved
+	} else {
+		m.savedViews = append(m.savedViews, saved)
+		i = len(m.savedViews) - 1
+	}
+
+	m.inputState.ExitInputMode()
+	m = m.showTab(tab{view: SavedView, saved: i})
+	m.cursor = 0
+	if err := m.saveViews(); err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+	m.errorState.ClearError()
+	return m, nil
+}
+
+// removeCurrentView deletes the saved view being shown
+func (m Model) removeCurrentView() (tea.Model, tea.Cmd) {
+	question := fmt.Sprintf("Remove the view %q? Its todos are kept.", m.savedViews[m.currentSaved].Name)
+	return m.confirmDeletion("🔎 Remove view", question, true, func(m Model) (tea.Model, tea.Cmd) {
+		return m.removeView(m.currentSaved)
+	})
+}
+
+// removeView removes saved view i and shows the tab to its left
+func (m Model) removeView(i int) (tea.Model, tea.Cmd) {
+	name := m.savedViews[i].Name
+	m.savedViews = append(m.savedViews[:i:i], m.savedViews[i+1:]...)
+
+	// Show the tab to the left of the removed one
+	m.currentView = GeneralView
+	if i > 0 {
+		m.currentView = SavedView
+		m.currentSaved = i - 1
+	}
+	m.cursor = 0
+	if view := m.currentSavedView(); view != nil {
+		m.cursor = view.cursor
+	}
+	m.collapseTodo()
+
+	if err := m.saveViews(); err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+	m.errorState.SetNotice(fmt.Sprintf("Removed view %q", name))
+	return m, nil
+}
+
+// moveCurrentView moves the tab of the saved view being shown by delta
+// places among the saved views
+func (m Model) moveCurrentView(delta int) (tea.Model, tea.Cmd) {
+	i, j := m.currentSaved, m.currentSaved+delta
+	if j < 0 || j >= len(m.savedViews) {
+		return m, nil
+	}
+
+	m.savedViews[i], m.savedViews[j] = m.savedViews[j], m.savedViews[i]
+	m.currentSaved = j
+	if err := m.saveViews(); err != nil {
+		m.errorState.SetError(err)
+	}
+	return m, nil
+}
+
+// saveViews writes the saved views, in tab order, to the settings file and
+// leaves the other settings in it as they are
+func (m *Model) saveViews() error {
+	views := make([]config.View, len(m.savedViews))
+	for i, view := range m.savedViews {
+		views[i] = view.View
+	}
+	err := m.cfg.Update(func(cfg *config.Config) {
+		cfg.Views = views
+	})
+	if err != nil {
+		return err
+	}
+	m.cfg.Views = views
+	return nil
+}
+
+// renderSavedView renders the todos a saved view selects
+func (m Model) renderSavedView() string {
+	// If in input mode, show the input form
+	if m.inputState.mode != NavigationMode {
+		return m.renderInputForm()
+	}
+
+	view := m.savedViews[m.currentSaved]
+	header := "🔎 " + view.Name + mutedStyle.Render("  "+view.Query)
+	if view.err != nil {
+		lines := []string{
+			header,
+			"",
+			errorStyle.Render("⚠ " + view.err.Error()),
+			"",
+			mutedStyle.Render("Press 'E' to edit the view or 'X' to remove it."),
+		}
+		return baseStyle.Render(strings.Join(lines, "\n"))
+	}
+
+	return m.renderTodoList(
+		header,
+		"No todos match this view!\n\nPress 'E' to edit its query.",
+		dateInline,
+	)
+}
+
+// renderSavedViewForm renders the form for saving a view
+func (m Model) renderSavedViewForm(errorDisplay string) string {
+	title := "🔎 Save View"
+	if m.inputState.editingView >= 0 {
+		title = "🔎 Edit View"
+	}
+
+	queryValue := m.renderFieldValue(m.inputState.description, descriptionField)
+	if q, err := query.Parse(m.inputState.description); err != nil {
+		queryValue += "\n  " + mutedStyle.Render(err.Error())
+	} else if todos, err := m.repository.Query(q, time.Now()); err == nil {
+		queryValue += "  " + mutedStyle.Render(fmt.Sprintf("(%d todos)", len(todos)))
+	}
+
+	form := []string{
+		title,
+		"",
+		errorDisplay,
+		m.renderFieldLabel("Name:", titleField),
+		"  " + m.renderFieldValue(m.inputState.title, titleField),
+		"",
+		m.renderFieldLabel("Query:", descriptionField),
+		"  " + queryValue,
+		"",
+		mutedStyle.Render(`Query: due:<7d tag:work status:open prio>=high text:"deploy" list:backend, or, not, -term, ( )`),
+		mutedStyle.Render("Tab: switch field • Enter/Ctrl+S: save • Esc: cancel"),
+	}
+	return baseStyle.Render(strings.Join(form, "\n"))
+}
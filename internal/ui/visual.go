@@ -0,0 +1,338 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/WasathTheekshana/tedo/internal/models"
+	"github.com/WasathTheekshana/tedo/internal/storage"
+)
+
+// startVisual enters visual mode in the current list view, marking the todo
+// under the cursor
+func (m Model) startVisual() (tea.Model, tea.Cmd) {
+	m.visual = true
+	m.marked = make(map[string]bool)
+	if todo := m.currentTodo(); todo != nil {
+		m.marked[todo.ID] = true
+	}
+	m.collapseTodo()
+	return m, nil
+}
+
+// exitVisual leaves visual mode and forgets the marked todos
+func (m *Model) exitVisual() {
+	m.visual = false
+	m.marked = nil
+}
+
+// isMovementKey reports whether key moves the cursor through a list view.
+// Visual mode leaves these to the view so todos on any page can be marked.
+func isMovementKey(key string) bool {
+	switch key {
+	case "j", "down", "k", "up", "ctrl+f", "page_down", "ctrl+b", "page_up":
+		return true
+	}
+	return false
+}
+
+// handleVisualKeys handles keys in visual mode, where todos are marked and
+// then acted on together
+func (m Model) handleVisualKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case " ", "v":
+		if todo := m.currentTodo(); todo != nil {
+			m.marked[todo.ID] = !m.marked[todo.ID]
+		}
+	case "a":
+		m.markAllVisible()
+	case "x":
+		return m.toggleMarkedTodos()
+	case "d":
+		return m.deleteMarkedTodos()
+	case "r":
+		return m.rescheduleMarkedTodos()
+	case "#":
+		return m.tagMarkedTodos()
+	case "m":
+		return m.moveMarkedTodos()
+	case "esc":
+		m.exitVisual()
+	}
+	return m, nil
+}
+
+// markAllVisible marks every todo that passes the filters, or unmarks them
+// when they are all marked already
+func (m *Model) markAllVisible() {
+	visible := m.visibleTodos()
+	all := true
+	for _, todo := range visible {
+		if !m.marked[todo.ID] {
+			all = false
+			break
+		}
+	}
+	for _, todo := range visible {
+		m.marked[todo.ID] = !all
+	}
+}
+
+// markedTodos returns the marked todos of the current view in display order
+func (m Model) markedTodos() []models.Todo {
+	var todos []models.Todo
+	for _, todo := range m.listTodos() {
+		if m.marked[todo.ID] {
+			todos = append(todos, todo)
+		}
+	}
+	return todos
+}
+
+// applyToMarked runs fn on every marked todo in one batch, so each file they
+// live in is loaded and saved once, then leaves visual mode and shows the
+// notice for the number of todos. When fn fails for any todo, none change.
+func (m Model) applyToMarked(notice func(n int) string, fn func(tx *storage.Repository, todo models.Todo) error) (tea.Model, tea.Cmd) {
+	todos := m.markedTodos()
+	err := m.repository.Batch(func(tx *storage.Repository) error {
+		for _, todo := range todos {
+			if err := fn(tx, todo); err != nil {
+				return fmt.Errorf("%q: %w", todo.Title, err)
+			}
+		}
+		return nil
+	})
+
+	// A failed batch saves nothing, so the marks stay for another try on
+	// freshly loaded todos
+	if err == nil {
+		m.exitVisual()
+	}
+	m.lastRefresh = time.Time{}
+	m.reloadTodos()
+	m.resetPagination()
+	if err != nil {
+		m.errorState.SetError(err)
+		return m, nil
+	}
+	m.errorState.SetNotice(notice(len(todos)))
+	return m, nil
+}
+
+// counted returns a notice that formats the number of todos into format
+func counted(format string) func(n int) string {
+	return func(n int) string {
+		return fmt.Sprintf(format, n)
+	}
+}
+
+// toggleMarkedTodos completes the marked todos, or reopens them when they
+// are all done already
+func (m Model) toggleMarkedTodos() (tea.Model, tea.Cmd) {
+	todos := m.markedTodos()
+	if len(todos) == 0 {
+		return m, nil
+	}
+
+	status, done := models.StatusDone, "Completed"
+	if allCompleted(todos) {
+		status, done = models.StatusOpen, "Reopened"
+	}
+	return m.applyToMarked(counted(done+" %d todo(s)"), func(tx *storage.Repository, todo models.Todo) error {
+		todo.SetStatus(status)
+		return tx.UpdateTodo(&todo)
+	})
+}
+
+// allCompleted reports whether every one of todos is completed
+func allCompleted(todos []models.Todo) bool {
+	for _, todo := range todos {
+		if !todo.Completed {
+			return false
+		}
+	}
+	return true
+}
+
+// deleteMarkedTodos moves the marked todos to the trash. Marked occurrences
+// of a recurring todo only skip their date.
+func (m Model) deleteMarkedTodos() (tea.Model, tea.Cmd) {
+	todos := m.markedTodos()
+	if len(todos) == 0 {
+		return m, nil
+	}
+
+	question, notice := fmt.Sprintf("Move %d todo(s) to the trash?", len(todos)), "Moved %d todo(s) to the trash"
+	for _, todo := range todos {
+		if todo.IsOccurrence() {
+			question, notice = fmt.Sprintf("Delete %d todo(s)? Occurrences of recurring todos only skip their date.", len(todos)), "Deleted %d todo(s)"
+			break
+		}
+	}
+	return m.confirmDeletion("Delete todos", question, false, func(m Model) (tea.Model, tea.Cmd) {
+		return m.applyToMarked(counted(notice), func(tx *storage.Repository, todo models.Todo) error {
+			return tx.DeleteTodo(todo)
+		})
+	})
+}
+
+// rescheduleMarkedTodos asks for a date and moves the marked todos to it
+func (m Model) rescheduleMarkedTodos() (tea.Model, tea.Cmd) {
+	n := len(m.markedTodos())
+	if n == 0 {
+		return m, nil
+	}
+
+	message := fmt.Sprintf("New date for %d todo(s), e.g. tomorrow, next fri or 2025-01-31. Leave it empty to file them in their lists.", n)
+	validate := func(value string) error {
+		_, err := ParseDateInput(value)
+		return err
+	}
+	m.prompt("Reschedule", message, "", validate, func(m Model, value string) (tea.Model, tea.Cmd) {
+		date, _ := ParseDateInput(value)
+		return m.applyToMarked(counted("Rescheduled %d todo(s)"), func(tx *storage.Repository, todo models.Todo) error {
+			return tx.MoveTodo(todo, date)
+		})
+	})
+	return m, nil
+}
+
+// tagMarkedTodos asks for tags to add to the marked todos, or to remove
+// from them when written as -tag
+func (m Model) tagMarkedTodos() (tea.Model, tea.Cmd) {
+	n := len(m.markedTodos())
+	if n == 0 {
+		return m, nil
+	}
+
+	message := fmt.Sprintf("Tags for %d todo(s), e.g. #work to add it or -#work to remove it.", n)
+	validate := func(value string) error {
+		add, remove, err := parseTagChanges(value)
+		if err == nil && len(add) == 0 && len(remove) == 0 {
+			err = errors.New("enter at least one tag")
+		}
+		return err
+	}
+	m.prompt("Tag todos", message, "", validate, func(m Model, value string) (tea.Model, tea.Cmd) {
+		add, remove, _ := parseTagChanges(value)
+		return m.applyToMarked(counted("Tagged %d todo(s)"), func(tx *storage.Repository, todo models.Todo) error {
+			todo.Tags = changeTags(todo.Tags, add, remove)
+			return tx.UpdateTodo(&todo)
+		})
+	})
+	return m, nil
+}
+
+// parseTagChanges splits a tag input into the tags to add and, written as
+// -tag or -#tag, the tags to remove
+func parseTagChanges(input string) (add, remove []string, err error) {
+	var adding, removing []string
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}) {
+		if tag, ok := strings.CutPrefix(field, "-"); ok {
+			removing = append(removing, tag)
+		} else {
+			adding = append(adding, field)
+		}
+	}
+
+	if add, err = models.ParseTags(strings.Join(adding, " ")); err != nil {
+		return nil, nil, err
+	}
+	if remove, err = models.ParseTags(strings.Join(removing, " ")); err != nil {
+		return nil, nil, err
+	}
+	return add, remove, nil
+}
+
+// changeTags returns tags without remove and with add appended
+func changeTags(tags, add, remove []string) []string {
+	var changed []string
+	for _, tag := range tags {
+		if !containsTag(remove, tag) {
+			changed = append(changed, tag)
+		}
+	}
+	for _, tag := range add {
+		if !containsTag(changed, tag) && !containsTag(remove, tag) {
+			changed = append(changed, tag)
+		}
+	}
+	return changed
+}
+
+// containsTag reports whether tags holds tag
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// moveMarkedTodos asks for a list and files the marked todos in it
+func (m Model) moveMarkedTodos() (tea.Model, tea.Cmd) {
+	n := len(m.markedTodos())
+	if n == 0 {
+		return m, nil
+	}
+
+	var names []string
+	for _, entry := range m.lists {
+		if !entry.list.Archived {
+			names = append(names, entry.list.Name)
+		}
+	}
+	message := fmt.Sprintf("List to move %d todo(s) to: %s", n, strings.Join(names, ", "))
+	repo := m.repository
+	validate := func(value string) error {
+		_, err := repo.FindList(value)
+		return err
+	}
+	m.prompt("Move to list", message, "", validate, func(m Model, value string) (tea.Model, tea.Cmd) {
+		list, err := m.repository.FindList(value)
+		if err != nil {
+			m.errorState.SetError(err)
+			return m, nil
+		}
+		notice := func(n int) string {
+			return fmt.Sprintf("Moved %d todo(s) to %s", n, list.Name)
+		}
+		return m.applyToMarked(notice, func(tx *storage.Repository, todo models.Todo) error {
+			return tx.MoveTodoToList(todo, list.ID)
+		})
+	})
+	return m, nil
+}
+
+// renderMark renders whether todo is marked, in front of it in visual mode
+func (m Model) renderMark(todo models.Todo) string {
+	if m.marked[todo.ID] {
+		return "■"
+	}
+	return "□"
+}
+
+// renderVisualFooter lists the keys of visual mode
+func (m Model) renderVisualFooter() string {
+	prompt := "Visual:"
+	help := []string{
+		"j/k: navigate",
+		"space/v: mark",
+		"a: mark all",
+		"x: toggle",
+		"d: delete",
+		"r: reschedule",
+		"#: tags",
+		"m: move to list",
+		"esc: cancel",
+	}
+	return footerStyle.Render(warningStyle.Render(prompt) + " " + strings.Join(help, " • "))
+}